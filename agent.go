@@ -0,0 +1,40 @@
+package yookassa
+
+// AgentType is the type of the agent selling the item on behalf of the supplier.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#agent-type
+type AgentType string
+
+const (
+	AgentTypeBankingPaymentAgent    AgentType = "banking_payment_agent"
+	AgentTypeBankingPaymentSubagent AgentType = "banking_payment_subagent"
+	AgentTypePaymentAgent           AgentType = "payment_agent"
+	AgentTypePaymentSubagent        AgentType = "payment_subagent"
+	AgentTypeAttorney               AgentType = "attorney"
+	AgentTypeCommissioner           AgentType = "commissioner"
+	AgentTypeAgent                  AgentType = "agent"
+)
+
+// IsValid reports whether the agent type is known.
+func (t AgentType) IsValid() bool {
+	switch t {
+	case AgentTypeBankingPaymentAgent, AgentTypeBankingPaymentSubagent, AgentTypePaymentAgent,
+		AgentTypePaymentSubagent, AgentTypeAttorney, AgentTypeCommissioner, AgentTypeAgent:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithAgent sets the agent type and the supplier of the item, as example for marketplaces
+// selling on behalf of partners.
+func WithAgent(agentType AgentType, supplier Supplier) ItemOption {
+	return func(i *Items) {
+		i.AgentType = agentType
+		i.Supplier = &supplier
+	}
+}
+
+// isValidINN reports whether the INN has 10 digits of a company or 12 digits of a person.
+func isValidINN(inn string) bool {
+	return (len(inn) == 10 || len(inn) == 12) && digitsPattern.MatchString(inn)
+}
@@ -0,0 +1,210 @@
+package yookassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned by AsyncNotificationHandler.Enqueue when the queue is full.
+	// The webhook handler answers 500 and YooKassa delivers the notification again later.
+	ErrQueueFull = errors.New("notification queue is full")
+	// ErrHandlerClosed is returned by AsyncNotificationHandler.Enqueue after shutdown has begun.
+	ErrHandlerClosed = errors.New("notification handler is closed")
+)
+
+// AsyncConfig configures the AsyncNotificationHandler.
+type AsyncConfig struct {
+	Workers         int           // Number of workers. Default 4
+	QueueSize       int           // Capacity of the default memory queue. Default 1000
+	MaxAttempts     int           // Attempts to handle a notification before dead-lettering. Default 3
+	Backoff         time.Duration // Delay between attempts, multiplied by the attempt number. Default 1 second
+	ShutdownTimeout time.Duration // Time to drain the queue after shutdown has begun. Default 30 seconds
+
+	// Queue holds the accepted notifications. Default is NewMemoryNotificationQueue(QueueSize),
+	// which loses the notifications on crash. Use a durable queue like SQLNotificationQueue,
+	// so notifications acknowledged to YooKassa are never lost.
+	Queue NotificationQueue
+
+	// DeadLetter receives notifications which failed all attempts with the last error. With a memory
+	// queue it also receives the notifications left in the queue when ShutdownTimeout expires,
+	// a durable queue keeps them to deliver again.
+	DeadLetter func(ctx context.Context, notification Notification, err error)
+}
+
+// AsyncNotificationHandler enqueues notifications so the webhook can answer YooKassa at once,
+// and handles them with a bounded pool of workers.
+//
+//	async := yookassa.NewAsyncNotificationHandler(fulfil, yookassa.AsyncConfig{})
+//	go async.Run(ctx)
+//	http.Handle("/webhook", yookassa.NewWebhookHandler(async.Enqueue))
+type AsyncNotificationHandler struct {
+	handle NotificationHandlerFunc
+	config AsyncConfig
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncNotificationHandler creates a handler which passes notifications to handle in the workers.
+func NewAsyncNotificationHandler(handle NotificationHandlerFunc, config AsyncConfig) *AsyncNotificationHandler {
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = time.Second
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = 30 * time.Second
+	}
+	if config.Queue == nil {
+		config.Queue = NewMemoryNotificationQueue(config.QueueSize)
+	}
+
+	return &AsyncNotificationHandler{
+		handle: handle,
+		config: config,
+	}
+}
+
+// Enqueue puts the notification into the queue without waiting. It implements NotificationHandlerFunc.
+// YooKassa receives 200 only after the queue stored the notification.
+func (h *AsyncNotificationHandler) Enqueue(ctx context.Context, notification Notification) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return ErrHandlerClosed
+	}
+
+	return h.config.Queue.Push(ctx, notification)
+}
+
+// Run starts the workers and blocks until ctx is done. Then it stops accepting notifications
+// and waits up to ShutdownTimeout for the notifications in flight; a memory queue is drained too.
+// When the timeout expires, the notifications in flight are interrupted. A durable queue keeps
+// them and delivers them again after the lease expires, a memory queue dead-letters them
+// together with the rest of the queue. Run must be called once.
+func (h *AsyncNotificationHandler) Run(ctx context.Context) {
+	baseCtx := context.WithoutCancel(ctx)
+	workCtx, cancel := context.WithCancel(baseCtx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < h.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.work(workCtx)
+		}()
+	}
+
+	<-ctx.Done()
+
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	_ = h.config.Queue.Close()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(h.config.ShutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	cancel()
+	<-done
+
+	if h.config.Queue.Durable() {
+		return
+	}
+
+	for {
+		item, err := h.config.Queue.Pop(baseCtx)
+		if err != nil {
+			return
+		}
+		h.deadLetter(baseCtx, item, fmt.Errorf("%w: shutdown timeout expired", ErrHandlerClosed))
+	}
+}
+
+// work handles notifications until the queue is closed and empty or ctx is done.
+func (h *AsyncNotificationHandler) work(ctx context.Context) {
+	for {
+		item, err := h.config.Queue.Pop(ctx)
+		switch {
+		case err == nil:
+			h.process(ctx, item)
+		case errors.Is(err, ErrQueueClosed) || ctx.Err() != nil:
+			return
+		default:
+			// The queue is unavailable, as example the database is down.
+			if !sleep(ctx, h.config.Backoff) {
+				return
+			}
+		}
+	}
+}
+
+func (h *AsyncNotificationHandler) process(ctx context.Context, item QueuedNotification) {
+	var err error
+	for attempt := 1; attempt <= h.config.MaxAttempts; attempt++ {
+		if attempt > 1 && !sleep(ctx, h.config.Backoff*time.Duration(attempt-1)) {
+			break
+		}
+
+		if err = h.handle(ctx, item.Notification); err == nil {
+			_ = h.config.Queue.Ack(context.WithoutCancel(ctx), item.ID)
+			return
+		}
+	}
+	if ctx.Err() != nil {
+		// Interrupted by the shutdown, not failed. A durable queue delivers it again.
+		if h.config.Queue.Durable() {
+			return
+		}
+		err = errors.Join(err, ctx.Err())
+	}
+
+	h.deadLetter(context.WithoutCancel(ctx), item, err)
+}
+
+// deadLetter passes the notification to DeadLetter and removes it from the queue.
+// If the removal fails, a durable queue delivers the notification again.
+func (h *AsyncNotificationHandler) deadLetter(ctx context.Context, item QueuedNotification, err error) {
+	if h.config.DeadLetter != nil {
+		h.config.DeadLetter(ctx, item.Notification, err)
+	}
+	_ = h.config.Queue.Ack(ctx, item.ID)
+}
+
+// sleep waits for d and reports false if ctx was done before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
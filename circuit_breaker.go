@@ -0,0 +1,148 @@
+package yookassa
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the API while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the CircuitBreaker.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests are passed to the API
+	CircuitOpen                         // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // Limited number of probe requests are passed to the API
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures the CircuitBreaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold    int           // Consecutive failures which open the circuit. Default 5
+	CoolDown            time.Duration // Time in the open state before probing the API. Default 30 seconds
+	HalfOpenMaxRequests int           // Concurrent probe requests in the half-open state. Default 1
+}
+
+// CircuitBreaker stops calling the API after consecutive failures. Only network errors
+// and 5xx responses are failures, 4xx responses including 429 never trip it. Local failures
+// before the request is sent, as example a broken credentials file, are not counted.
+// One breaker may be shared by several clients of the same shop.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	inFlight  int
+	changedAt time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+
+	return &CircuitBreaker{config: config, changedAt: time.Now()}
+}
+
+// State returns the current state. It is safe to use in health checks.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.coolDown(time.Now())
+
+	return b.state
+}
+
+// StateChangedAt returns the time of the last state change.
+func (b *CircuitBreaker) StateChangedAt() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.changedAt
+}
+
+// allow reports whether the request may be performed. Allowed requests must be finished with done.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.coolDown(time.Now())
+
+	switch b.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.inFlight >= b.config.HalfOpenMaxRequests {
+			return ErrCircuitOpen
+		}
+	}
+
+	b.inFlight++
+
+	return nil
+}
+
+// done records the result of the allowed request. Requests canceled by the caller and
+// failures before the request was sent, as example of the credentials provider, are not counted.
+func (b *CircuitBreaker) done(statusCode int, skip bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight--
+	if skip {
+		return
+	}
+
+	if statusCode != 0 && statusCode < http.StatusInternalServerError {
+		b.failures = 0
+		if b.state == CircuitHalfOpen {
+			b.setState(CircuitClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = time.Now()
+		b.setState(CircuitOpen)
+	}
+}
+
+func (b *CircuitBreaker) coolDown(now time.Time) {
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.config.CoolDown {
+		b.setState(CircuitHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	if b.state != state {
+		b.state = state
+		b.changedAt = time.Now()
+	}
+}
@@ -4,30 +4,64 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 )
 
+// Names of the operations performed by the client. They are reported to
+// request hooks and used by the instrumentation subpackages.
+const (
+	OperationCreatePayment = "payments.create"
+	OperationGetPayment    = "payments.get"
+	OperationCreateReceipt = "receipts.create"
+	OperationGetReceipt    = "receipts.get"
+	OperationListReceipts  = "receipts.list"
+	OperationGetRefund     = "refunds.get"
+)
+
 type yooKassaClient struct {
-	baseURL string
-	shopID  string
-	apiKEY  string
-	httpClient *http.Client
+	baseURL     string
+	credentials CredentialsProvider
+	httpClient  *http.Client
+
+	maxAttempts  int
+	retryBackoff time.Duration
+	requestHooks []func(ctx context.Context, info RequestInfo)
+	rateLimiters map[string]*rateLimiter
+	breaker      *CircuitBreaker
+	pollBackoff  PollBackoff
 }
 
+// RequestInfo describes a single HTTP exchange with the YooKassa API.
+type RequestInfo struct {
+	Operation  string        // Name of the operation. As example "payments.create"
+	Method     string        // HTTP method of the request
+	URL        string        // Requested URL
+	Attempt    int           // Number of the attempt starting from 1
+	StatusCode int           // HTTP status of the response. Zero if no response was received
+	Duration   time.Duration // Time spent on the attempt
+	Err        error         // Error of the attempt. *APIError for unsuccessful responses
 
+	RequestBody  []byte // Body of the request. It is not redacted
+	ResponseBody []byte // Body of the response. It is not redacted
+}
 
 // NewConfig creates a newConfig yooKassaClient with the given shop ID, API key, and options.
 // It returns a pointer to the yooKassaClient.
+//
+// Use WithCredentialsProvider to rotate the keys without creating a new client.
 func NewConfig(shopId, apiKey string, opts ...func(c *yooKassaClient)) *yooKassaClient {
 	c := &yooKassaClient{
-		baseURL: "https://api.yookassa.ru/v3/payments",
-		shopID:  shopId,
-		apiKEY:  apiKey,
-		httpClient:    &http.Client{},
+		baseURL:     "https://api.yookassa.ru/v3",
+		credentials: StaticCredentials(shopId, apiKey),
+		httpClient:  &http.Client{},
+		maxAttempts: 1,
+		pollBackoff: defaultPollBackoff,
 	}
 
 	for _, o := range opts {
@@ -37,77 +71,207 @@ func NewConfig(shopId, apiKey string, opts ...func(c *yooKassaClient)) *yooKassa
 	return c
 }
 
-func (c *yooKassaClient) doRequest(method string, payload io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, c.baseURL, payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// do performs the operation and decodes the response into out.
+//
+// The request is retried on network errors, 429 and 5xx responses if retries are enabled.
+// All attempts of one operation share the same Idempotence-Key.
+func (c *yooKassaClient) do(ctx context.Context, operation, method, url string, payload, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(payload); err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = buf.Bytes()
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Idempotence-Key", uuid.NewV4().String())
-	req.SetBasicAuth(c.shopID, c.apiKEY)
+	var idempotenceKey string
+	if method == http.MethodPost {
+		idempotenceKey = IdempotenceKeyFromContext(ctx)
+		if idempotenceKey == "" {
+			idempotenceKey = uuid.NewV4().String()
+		}
+	}
 
-	return c.httpClient.Do(req)
-}
+	var err error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err = c.waitRetry(ctx, attempt, err); err != nil {
+				return err
+			}
+		}
 
-func (c *yooKassaClient) sendRequest(method string, payload interface{}) (*http.Response, error) {
-	var buf bytes.Buffer
-	if payload != nil {
-		if err := json.NewEncoder(&buf).Encode(payload); err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		if c.breaker != nil {
+			if err = c.breaker.allow(); err != nil {
+				return err
+			}
+		}
+
+		limiter := c.rateLimiters[operationClass(method)]
+		if limiter != nil {
+			if err = limiter.wait(ctx); err != nil {
+				if c.breaker != nil {
+					c.breaker.done(0, true)
+				}
+				return err
+			}
+		}
+
+		start := time.Now()
+		var (
+			statusCode int
+			respBody   []byte
+		)
+		statusCode, respBody, err = c.doRequest(ctx, method, url, idempotenceKey, body, out)
+		if c.breaker != nil {
+			c.breaker.done(statusCode, ctx.Err() != nil || errors.As(err, new(*notSentError)))
+		}
+		if limiter != nil {
+			limiter.release()
+			if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0 {
+				limiter.pause(time.Duration(apiErr.RetryAfter) * time.Millisecond)
+			}
+		}
+		c.runHooks(ctx, RequestInfo{
+			Operation:    operation,
+			Method:       method,
+			URL:          url,
+			Attempt:      attempt,
+			StatusCode:   statusCode,
+			Duration:     time.Since(start),
+			Err:          err,
+			RequestBody:  body,
+			ResponseBody: respBody,
+		})
+
+		if err == nil || !isRetryable(ctx, statusCode) {
+			return err
 		}
 	}
 
-	return c.doRequest(method, &buf)
+	return err
 }
 
-// SendPaymentRequest sends a payment request to YooKassa and returns the payment response and any error encountered.
-//
-// It takes a paymentRequest of type PaymentRequest and returns a PaymentResponse and an error.
-func (c *yooKassaClient) SendPaymentRequest(paymentRequest PaymentRequest) (PaymentResponse, error) {
-	resp, err := c.sendRequest(http.MethodPost, paymentRequest)
+func (c *yooKassaClient) doRequest(ctx context.Context, method, url, idempotenceKey string, body []byte, out interface{}) (int, []byte, error) {
+	var payload io.Reader
+	if body != nil {
+		payload = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
 	if err != nil {
-		return PaymentResponse{}, err
+		return 0, nil, &notSentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+
+	credentials, err := c.credentials.Credentials(ctx)
+	if err != nil {
+		return 0, nil, &notSentError{fmt.Errorf("failed to get credentials: %w", err)}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if idempotenceKey != "" {
+		req.Header.Set("Idempotence-Key", idempotenceKey)
+	}
+	req.SetBasicAuth(credentials.ShopID, credentials.APIKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return PaymentResponse{}, fmt.Errorf("request failed with status %s", resp.Status)
+		return resp.StatusCode, respBody, newAPIError(resp, respBody)
 	}
 
-	var paymentResponse PaymentResponse
-	if err = json.NewDecoder(resp.Body).Decode(&paymentResponse); err != nil {
-		return PaymentResponse{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return resp.StatusCode, respBody, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return paymentResponse, nil
+	return resp.StatusCode, respBody, nil
 }
 
-// GetPayment retrieves a payment with the given ID from the YooKassa API.
-func (c *yooKassaClient) GetPayment(ctx context.Context, paymentID string) (PaymentResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+paymentID, nil)
-	if err != nil {
-		return PaymentResponse{}, fmt.Errorf("failed to create request: %w", err)
+// notSentError is a failure which happened before the request was sent to the API.
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notSentError) Unwrap() error {
+	return e.err
+}
+
+func (c *yooKassaClient) runHooks(ctx context.Context, info RequestInfo) {
+	for _, hook := range c.requestHooks {
+		hook(ctx, info)
 	}
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.shopID, c.apiKEY)
+// waitRetry sleeps before the given attempt. The delay grows linearly with the attempt
+// number unless the API asked to retry after a specific time.
+func (c *yooKassaClient) waitRetry(ctx context.Context, attempt int, lastErr error) error {
+	delay := c.retryBackoff * time.Duration(attempt-1)
+	if apiErr, ok := AsAPIError(lastErr); ok && apiErr.RetryAfter > 0 {
+		delay = time.Duration(apiErr.RetryAfter) * time.Millisecond
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return PaymentResponse{}, fmt.Errorf("failed to send request: %w", err)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return PaymentResponse{}, fmt.Errorf("request failed with status %s", resp.Status)
+// isRetryable reports whether the failed attempt with the given status should be retried.
+// Zero status means that no response was received.
+func isRetryable(ctx context.Context, statusCode int) bool {
+	if ctx.Err() != nil {
+		return false
 	}
 
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// SendPaymentRequest sends a payment request to YooKassa and returns the payment response and any error encountered.
+//
+// It takes a paymentRequest of type PaymentRequest and returns a PaymentResponse and an error.
+func (c *yooKassaClient) SendPaymentRequest(ctx context.Context, paymentRequest PaymentRequest) (PaymentResponse, error) {
 	var paymentResponse PaymentResponse
-	if err = json.NewDecoder(resp.Body).Decode(&paymentResponse); err != nil {
-		return PaymentResponse{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.do(ctx, OperationCreatePayment, http.MethodPost, c.baseURL+"/payments", paymentRequest, &paymentResponse); err != nil {
+		return PaymentResponse{}, err
 	}
 
 	return paymentResponse, nil
 }
 
+// SendRawPaymentRequest sends the encoded payment request as is, as example a request stored by Outbox.
+func (c *yooKassaClient) SendRawPaymentRequest(ctx context.Context, paymentRequest json.RawMessage) (PaymentResponse, error) {
+	var paymentResponse PaymentResponse
+	if err := c.do(ctx, OperationCreatePayment, http.MethodPost, c.baseURL+"/payments", paymentRequest, &paymentResponse); err != nil {
+		return PaymentResponse{}, err
+	}
+
+	return paymentResponse, nil
+}
+
+// GetPayment retrieves a payment with the given ID from the YooKassa API.
+func (c *yooKassaClient) GetPayment(ctx context.Context, paymentID string) (PaymentResponse, error) {
+	var paymentResponse PaymentResponse
+	if err := c.do(ctx, OperationGetPayment, http.MethodGet, c.baseURL+"/payments/"+paymentID, nil, &paymentResponse); err != nil {
+		return PaymentResponse{}, err
+	}
+
+	return paymentResponse, nil
+}
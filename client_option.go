@@ -1,12 +1,21 @@
 package yookassa
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"time"
 )
 
+// WithBaseURL sets the root of the API. By default it is "https://api.yookassa.ru/v3".
+//
+// Earlier versions expected the URL of the payments endpoint, as example
+// "https://api.yookassa.ru/v3/payments". Such URLs are still accepted: the trailing "/payments"
+// is trimmed, so existing callers keep working. Pass the root in new code.
 func WithBaseURL(url string) func(*yooKassaClient) {
 	return func(c *yooKassaClient) {
-		c.baseURL = url
+		url = strings.TrimSuffix(url, "/")
+		c.baseURL = strings.TrimSuffix(url, "/payments")
 	}
 }
 
@@ -14,4 +23,66 @@ func WithHTTPClient(client *http.Client) func(*yooKassaClient) {
 	return func(c *yooKassaClient) {
 		c.httpClient = client
 	}
-}
\ No newline at end of file
+}
+
+// WithRetry enables retries of failed requests. The request is performed at most maxAttempts times,
+// the delay between attempts grows by backoff each time or follows retry_after from the API.
+func WithRetry(maxAttempts int, backoff time.Duration) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		c.maxAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// WithRequestHook registers a function called after every HTTP attempt.
+// The context is the one passed to the client method.
+func WithRequestHook(hook func(ctx context.Context, info RequestInfo)) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}
+}
+
+// WithCredentialsProvider replaces the shop ID and API key given to NewConfig with the provider
+// consulted before every request.
+func WithCredentialsProvider(provider CredentialsProvider) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		c.credentials = provider
+	}
+}
+
+// WithRateLimit limits requests of the operation class (OperationClassCreate or OperationClassRead).
+// Requests wait for the limiter respecting the context. When the API answers 429 with retry_after,
+// all requests of the class are paused for that time. Every client gets its own limiter.
+func WithRateLimit(class string, limit RateLimit) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		if c.rateLimiters == nil {
+			c.rateLimiters = make(map[string]*rateLimiter)
+		}
+		c.rateLimiters[class] = newRateLimiter(limit)
+	}
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen while the breaker is open.
+func WithCircuitBreaker(breaker *CircuitBreaker) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		c.breaker = breaker
+	}
+}
+
+// WithPollBackoff configures the polling of WaitForStatus and Watch. Zero fields keep the defaults.
+func WithPollBackoff(backoff PollBackoff) func(*yooKassaClient) {
+	return func(c *yooKassaClient) {
+		if backoff.Initial > 0 {
+			c.pollBackoff.Initial = backoff.Initial
+		}
+		if backoff.Max > 0 {
+			c.pollBackoff.Max = backoff.Max
+		}
+		if backoff.Multiplier >= 1 {
+			c.pollBackoff.Multiplier = backoff.Multiplier
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package yookassa
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials are used for the basic authentication in the YooKassa API.
+type Credentials struct {
+	ShopID string // ID of the shop
+	APIKey string // Secret key of the shop
+}
+
+// CredentialsProvider is consulted before every request, so keys can be rotated without restart.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc is an adapter to use ordinary functions as CredentialsProvider.
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns the same credentials for every request.
+func StaticCredentials(shopID, apiKey string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{ShopID: shopID, APIKey: apiKey}, nil
+	})
+}
+
+// EnvCredentials reads the credentials from the environment variables on every request.
+func EnvCredentials(shopIDVar, apiKeyVar string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		shopID, ok := os.LookupEnv(shopIDVar)
+		if !ok || shopID == "" {
+			return Credentials{}, fmt.Errorf("environment variable %s is not set", shopIDVar)
+		}
+
+		apiKey, ok := os.LookupEnv(apiKeyVar)
+		if !ok || apiKey == "" {
+			return Credentials{}, fmt.Errorf("environment variable %s is not set", apiKeyVar)
+		}
+
+		return Credentials{ShopID: shopID, APIKey: apiKey}, nil
+	})
+}
+
+// FileCredentials reads the shop ID and the secret key from two files, as example
+// mounted from a secret manager. The files are reloaded when their modification time
+// or size changes. Surrounding whitespace is trimmed.
+type FileCredentials struct {
+	shopIDFile string
+	apiKeyFile string
+
+	mu          sync.Mutex
+	credentials Credentials
+	versions    [2]fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials creates a provider which reads the credentials from the files.
+func NewFileCredentials(shopIDFile, apiKeyFile string) *FileCredentials {
+	return &FileCredentials{
+		shopIDFile: shopIDFile,
+		apiKeyFile: apiKeyFile,
+	}
+}
+
+// Credentials returns the current content of the files.
+func (p *FileCredentials) Credentials(context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	shopID, shopIDVersion, err := p.reload(p.shopIDFile, p.versions[0], p.credentials.ShopID)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	apiKey, apiKeyVersion, err := p.reload(p.apiKeyFile, p.versions[1], p.credentials.APIKey)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	// Versions are updated only after both files are read, so a failed read is retried next time.
+	p.credentials = Credentials{ShopID: shopID, APIKey: apiKey}
+	p.versions = [2]fileVersion{shopIDVersion, apiKeyVersion}
+
+	return p.credentials, nil
+}
+
+// reload reads the file if it changed since the last read, otherwise returns the current value.
+// It returns the value together with the version of the file it was read from.
+func (p *FileCredentials) reload(path string, version fileVersion, current string) (string, fileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fileVersion{}, fmt.Errorf("failed to stat credentials file: %w", err)
+	}
+
+	actual := fileVersion{modTime: info.ModTime(), size: info.Size()}
+	if actual == version && current != "" {
+		return current, version, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fileVersion{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fileVersion{}, fmt.Errorf("credentials file %s is empty", path)
+	}
+
+	return value, actual, nil
+}
@@ -0,0 +1,51 @@
+package yookassa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when YooKassa responds with an unsuccessful status.
+// See https://yookassa.ru/developers/using-api/response-handling/response-format#error
+type APIError struct {
+	StatusCode  int    `json:"-"`                     // HTTP status of the response
+	Status      string `json:"-"`                     // HTTP status text of the response. As example "400 Bad Request"
+	Type        string `json:"type"`                  // Type of the object. Always "error"
+	ID          string `json:"id"`                    // ID of the error. Use it for contact with support
+	Code        string `json:"code"`                  // Code of the error. As example "invalid_request"
+	Description string `json:"description,omitempty"` // Description of the error
+	Parameter   string `json:"parameter,omitempty"`   // Name of the parameter which caused the error
+	RetryAfter  int    `json:"retry_after,omitempty"` // Recommended delay in milliseconds before the retry
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("request failed with status %s", e.Status)
+	}
+
+	return fmt.Sprintf("request failed with status %s: %s: %s", e.Status, e.Code, e.Description)
+}
+
+// AsAPIError reports whether err is an *APIError and returns it.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// newAPIError builds an APIError from the unsuccessful response.
+// The body is parsed on a best effort basis because proxies may return non JSON errors.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{}
+	_ = json.Unmarshal(body, apiErr)
+
+	apiErr.StatusCode = resp.StatusCode
+	apiErr.Status = resp.Status
+
+	return apiErr
+}
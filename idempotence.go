@@ -0,0 +1,29 @@
+package yookassa
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotenceKeyLifetime is the time YooKassa honors the Idempotence-Key for.
+// See https://yookassa.ru/developers/using-api/interaction-format#idempotence
+const IdempotenceKeyLifetime = 24 * time.Hour
+
+// idempotenceKeyWindow is the age after which a key is not reused. The margin covers
+// the request time and the clock skew with YooKassa.
+const idempotenceKeyWindow = IdempotenceKeyLifetime - time.Hour
+
+type idempotenceKeyContextKey struct{}
+
+// WithIdempotenceKey returns a context which makes the client send the key as Idempotence-Key
+// instead of a random one. Repeating a request with the same key within 24 hours returns
+// the result of the first request instead of creating a new object.
+func WithIdempotenceKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotenceKeyContextKey{}, key)
+}
+
+// IdempotenceKeyFromContext returns the key set by WithIdempotenceKey or "".
+func IdempotenceKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotenceKeyContextKey{}).(string)
+	return key
+}
@@ -0,0 +1,252 @@
+package yookassa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrJournalEntryExpired is returned by JournaledClient when the entry has no response and its
+// Idempotence-Key is about to expire. The payment may have been created by a request whose response
+// was lost, so it is not sent again. Find the payment, as example by the metadata, and store it
+// with IdempotencyJournal.Complete, or remove the entry with IdempotencyJournal.Release if there is none.
+var ErrJournalEntryExpired = errors.New("journal entry expired without a response")
+
+// JournalEntry maps a business key to the Idempotence-Key and the response of the operation.
+type JournalEntry struct {
+	BusinessKey    string           // Key of the caller, see BusinessKey
+	IdempotenceKey string           // Key sent to YooKassa
+	Response       *PaymentResponse // Response of the API. Nil until the operation succeeds
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IdempotencyJournal stores JournalEntry by business keys.
+type IdempotencyJournal interface {
+	// Reserve returns the entry of the business key. If there is none it is created with the idempotence key.
+	// Concurrent calls with the same business key must return the same entry.
+	Reserve(ctx context.Context, businessKey, idempotenceKey string) (JournalEntry, error)
+	// Complete stores the response of the operation.
+	Complete(ctx context.Context, businessKey string, response PaymentResponse) error
+	// Release removes the entry of the business key if it has the idempotence key and no response,
+	// so the next call reserves a new idempotence key.
+	Release(ctx context.Context, businessKey, idempotenceKey string) error
+}
+
+// BusinessKey joins the order ID and the operation, as example BusinessKey("order-1", "create").
+func BusinessKey(orderID, operation string) string {
+	return orderID + ":" + operation
+}
+
+// JournaledClient creates payments at most once per business key.
+//
+// Repeated calls return the stored response without calling the API. Calls which did not get
+// a response are repeated with the same Idempotence-Key, so even concurrent duplicates on
+// different replicas create a single payment. Duplicates within the process are serialized.
+// Requests rejected with a 4xx error create no payment, so their reservation is released and
+// the corrected request is sent with a new Idempotence-Key. Entries without a response are not
+// repeated after IdempotenceKeyLifetime, see ErrJournalEntryExpired.
+type JournaledClient struct {
+	sender  PaymentSender
+	journal IdempotencyJournal
+	locks   keyedMutex
+}
+
+// NewJournaledClient creates a client which records operations of the sender in the journal.
+func NewJournaledClient(sender PaymentSender, journal IdempotencyJournal) *JournaledClient {
+	return &JournaledClient{sender: sender, journal: journal}
+}
+
+// SendPaymentRequest creates the payment once for the business key.
+func (c *JournaledClient) SendPaymentRequest(ctx context.Context, businessKey string, paymentRequest PaymentRequest) (PaymentResponse, error) {
+	unlock := c.locks.lock(businessKey)
+	defer unlock()
+
+	entry, err := c.journal.Reserve(ctx, businessKey, uuid.NewV4().String())
+	if err != nil {
+		return PaymentResponse{}, fmt.Errorf("failed to reserve journal entry: %w", err)
+	}
+	if entry.Response != nil {
+		return *entry.Response, nil
+	}
+	if time.Since(entry.CreatedAt) > idempotenceKeyWindow {
+		return PaymentResponse{}, fmt.Errorf("%w: business key %q, idempotence key %q",
+			ErrJournalEntryExpired, businessKey, entry.IdempotenceKey)
+	}
+
+	resp, err := c.sender.SendPaymentRequest(WithIdempotenceKey(ctx, entry.IdempotenceKey), paymentRequest)
+	if apiErr, ok := AsAPIError(err); ok && isClientError(apiErr.StatusCode) {
+		if releaseErr := c.journal.Release(ctx, businessKey, entry.IdempotenceKey); releaseErr != nil {
+			return PaymentResponse{}, errors.Join(err, fmt.Errorf("failed to release journal entry: %w", releaseErr))
+		}
+	}
+	if err != nil {
+		return PaymentResponse{}, err
+	}
+
+	if err = c.journal.Complete(ctx, businessKey, resp); err != nil {
+		return resp, fmt.Errorf("failed to complete journal entry: %w", err)
+	}
+
+	return resp, nil
+}
+
+// MemoryIdempotencyJournal keeps entries in memory. It protects a single instance only.
+type MemoryIdempotencyJournal struct {
+	mu      sync.Mutex
+	entries map[string]JournalEntry
+}
+
+// NewMemoryIdempotencyJournal creates an empty journal.
+func NewMemoryIdempotencyJournal() *MemoryIdempotencyJournal {
+	return &MemoryIdempotencyJournal{entries: make(map[string]JournalEntry)}
+}
+
+func (j *MemoryIdempotencyJournal) Reserve(_ context.Context, businessKey, idempotenceKey string) (JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry, ok := j.entries[businessKey]; ok {
+		return entry, nil
+	}
+
+	now := time.Now().UTC()
+	entry := JournalEntry{
+		BusinessKey:    businessKey,
+		IdempotenceKey: idempotenceKey,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	j.entries[businessKey] = entry
+
+	return entry, nil
+}
+
+func (j *MemoryIdempotencyJournal) Complete(_ context.Context, businessKey string, response PaymentResponse) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.entries[businessKey]
+	if !ok {
+		return fmt.Errorf("journal entry %q not found", businessKey)
+	}
+
+	entry.Response = &response
+	entry.UpdatedAt = time.Now().UTC()
+	j.entries[businessKey] = entry
+
+	return nil
+}
+
+func (j *MemoryIdempotencyJournal) Release(_ context.Context, businessKey, idempotenceKey string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry, ok := j.entries[businessKey]; ok && entry.Response == nil && entry.IdempotenceKey == idempotenceKey {
+		delete(j.entries, businessKey)
+	}
+
+	return nil
+}
+
+// SQLIdempotencyJournal keeps entries in a database table shared by replicas. Create the table with Migrate.
+type SQLIdempotencyJournal struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+}
+
+// NewSQLIdempotencyJournal creates a journal in the table. The table name is not escaped.
+func NewSQLIdempotencyJournal(db *sql.DB, table string, placeholder SQLPlaceholder) *SQLIdempotencyJournal {
+	return &SQLIdempotencyJournal{db: db, table: table, placeholder: placeholder}
+}
+
+// Migrate creates the table if it does not exist.
+func (j *SQLIdempotencyJournal) Migrate(ctx context.Context) error {
+	_, err := j.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+j.table+` (
+	business_key VARCHAR(255) NOT NULL PRIMARY KEY,
+	idempotence_key VARCHAR(64) NOT NULL,
+	response TEXT,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create journal table: %w", err)
+	}
+
+	return nil
+}
+
+// Reserve inserts the entry relying on the primary key, so only the first of concurrent
+// replicas stores its idempotence key and all of them read it back.
+func (j *SQLIdempotencyJournal) Reserve(ctx context.Context, businessKey, idempotenceKey string) (JournalEntry, error) {
+	entry, err := j.get(ctx, businessKey)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return entry, err
+	}
+
+	now := time.Now().UTC()
+	_, insertErr := j.db.ExecContext(ctx,
+		j.placeholder.rebind(`INSERT INTO `+j.table+` (business_key, idempotence_key, created_at, updated_at) VALUES (?, ?, ?, ?)`),
+		businessKey, idempotenceKey, now, now,
+	)
+
+	entry, err = j.get(ctx, businessKey)
+	if errors.Is(err, sql.ErrNoRows) && insertErr != nil {
+		return JournalEntry{}, insertErr
+	}
+
+	return entry, err
+}
+
+func (j *SQLIdempotencyJournal) Complete(ctx context.Context, businessKey string, response PaymentResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payment response: %w", err)
+	}
+
+	_, err = j.db.ExecContext(ctx,
+		j.placeholder.rebind(`UPDATE `+j.table+` SET response = ?, updated_at = ? WHERE business_key = ?`),
+		string(data), time.Now().UTC(), businessKey,
+	)
+
+	return err
+}
+
+func (j *SQLIdempotencyJournal) Release(ctx context.Context, businessKey, idempotenceKey string) error {
+	_, err := j.db.ExecContext(ctx,
+		j.placeholder.rebind(`DELETE FROM `+j.table+` WHERE business_key = ? AND idempotence_key = ? AND response IS NULL`),
+		businessKey, idempotenceKey,
+	)
+
+	return err
+}
+
+func (j *SQLIdempotencyJournal) get(ctx context.Context, businessKey string) (JournalEntry, error) {
+	var (
+		entry    JournalEntry
+		response sql.NullString
+	)
+	err := j.db.QueryRowContext(ctx,
+		j.placeholder.rebind(`SELECT business_key, idempotence_key, response, created_at, updated_at FROM `+j.table+` WHERE business_key = ?`),
+		businessKey,
+	).Scan(&entry.BusinessKey, &entry.IdempotenceKey, &response, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	if response.Valid {
+		entry.Response = &PaymentResponse{}
+		if err = json.Unmarshal([]byte(response.String), entry.Response); err != nil {
+			return JournalEntry{}, fmt.Errorf("failed to unmarshal payment response: %w", err)
+		}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,61 @@
+package yookassa
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LoggerOption configures WithLogger.
+type LoggerOption func(*loggerConfig)
+
+type loggerConfig struct {
+	personalDataKey []byte
+}
+
+// WithPersonalDataKey sets the key of HashPersonalData, so customers can be matched by hashes
+// in the logs. Without it personal data is replaced with "[REDACTED]".
+func WithPersonalDataKey(key []byte) LoggerOption {
+	return func(c *loggerConfig) {
+		c.personalDataKey = append([]byte(nil), key...)
+	}
+}
+
+// WithLogger logs every HTTP attempt with the request and response bodies.
+// Bodies are redacted with RedactJSON. Successful attempts are logged with the debug level,
+// failed ones with the warning level.
+func WithLogger(logger *slog.Logger, opts ...LoggerOption) func(*yooKassaClient) {
+	var config loggerConfig
+	for _, o := range opts {
+		o(&config)
+	}
+
+	return WithRequestHook(func(ctx context.Context, info RequestInfo) {
+		level := slog.LevelDebug
+		if info.Err != nil {
+			level = slog.LevelWarn
+		}
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("operation", info.Operation),
+			slog.String("method", info.Method),
+			slog.String("url", info.URL),
+			slog.Int("attempt", info.Attempt),
+			slog.Int("status", info.StatusCode),
+			slog.Duration("duration", info.Duration),
+		}
+		if len(info.RequestBody) > 0 {
+			attrs = append(attrs, slog.String("request", string(RedactJSON(info.RequestBody, config.personalDataKey))))
+		}
+		if len(info.ResponseBody) > 0 {
+			attrs = append(attrs, slog.String("response", string(RedactJSON(info.ResponseBody, config.personalDataKey))))
+		}
+		if info.Err != nil {
+			attrs = append(attrs, slog.String("error", info.Err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, "yookassa request", attrs...)
+	})
+}
@@ -0,0 +1,219 @@
+package yookassa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// groupSeparator is the GS character separating variable length fields of GS1 codes.
+const groupSeparator = "\x1d"
+
+// ErrInvalidMarkCode is returned by ParseMarkCode for malformed codes.
+var ErrInvalidMarkCode = errors.New("invalid mark code")
+
+// Formats of the mark codes. They match the fields of MarkCodeInfo.
+const (
+	MarkCodeFormatUnknown = "unknown"
+	MarkCodeFormatEan8    = "ean_8"
+	MarkCodeFormatEan13   = "ean_13"
+	MarkCodeFormatItf14   = "itf_14"
+	MarkCodeFormatGs10    = "gs_10"
+	MarkCodeFormatGs1m    = "gs_1m"
+	MarkCodeFormatShort   = "short"
+	MarkCodeFormatFur     = "fur"
+	MarkCodeFormatEgais20 = "egais_20"
+	MarkCodeFormatEgais30 = "egais_30"
+)
+
+var (
+	digitsPattern      = regexp.MustCompile(`^[0-9]+$`)
+	furPattern         = regexp.MustCompile(`^[A-Z]{2}-[0-9]{6}-[A-Z]{3}[0-9]{7}$`)
+	egaisPattern       = regexp.MustCompile(`^[0-9A-Z]+$`)
+	shortPattern       = regexp.MustCompile(`^[0-9]{14}[!-~]{15}$`)
+	symbologyIDPattern = regexp.MustCompile(`^\][A-Za-z][0-9]`)
+)
+
+// gs1FixedLengths holds the length of the data of GS1 application identifiers with fixed length.
+// Other identifiers are terminated by GS or the end of the code.
+var gs1FixedLengths = map[string]int{
+	"01":   14, // GTIN
+	"11":   6,  // Production date
+	"17":   6,  // Expiration date
+	"8005": 6,  // Maximum retail price of tobacco
+	"3103": 6,  // Net weight in kilograms
+	"3353": 6,  // Volume in liters
+	"7003": 10, // Expiration date and time
+}
+
+// gs1VariableIdentifiers are application identifiers with variable length used in Chestny ZNAK codes.
+var gs1VariableIdentifiers = []string{"21", "10", "91", "92", "93", "240"}
+
+// MarkCode is the parsed mark code.
+type MarkCode struct {
+	Raw    string            // Code as scanned without the symbology identifier
+	Format string            // One of MarkCodeFormat constants
+	GTIN   string            // Global trade item number. Empty for codes without it
+	Serial string            // Serial number of the item. Empty for codes without it
+	Fields map[string]string // GS1 application identifiers and their data
+}
+
+// Info returns MarkCodeInfo with the field of the format filled.
+func (m MarkCode) Info() MarkCodeInfo {
+	var info MarkCodeInfo
+	switch m.Format {
+	case MarkCodeFormatEan8:
+		info.Ean8 = m.Raw
+	case MarkCodeFormatEan13:
+		info.Ean13 = m.Raw
+	case MarkCodeFormatItf14:
+		info.Itf14 = m.Raw
+	case MarkCodeFormatGs10:
+		info.Gs10 = m.Raw
+	case MarkCodeFormatGs1m:
+		info.Gs1m = m.Raw
+	case MarkCodeFormatShort:
+		info.Short = m.Raw
+	case MarkCodeFormatFur:
+		info.Fur = m.Raw
+	case MarkCodeFormatEgais20:
+		info.Egails20 = m.Raw
+	case MarkCodeFormatEgais30:
+		info.Egails30 = m.Raw
+	default:
+		info.Unknown = m.Raw
+	}
+
+	return info
+}
+
+// ItemOption returns the option setting the mark code of the receipt item.
+func (m MarkCode) ItemOption() ItemOption {
+	return WithMarking(m.Info())
+}
+
+// ParseMarkCode detects the format of the scanned code and extracts GTIN and serial number.
+// GS1 codes must keep the GS separators (0x1D) between variable length fields.
+// Codes of unrecognized formats are returned with MarkCodeFormatUnknown.
+func ParseMarkCode(raw string) (MarkCode, error) {
+	code := strings.TrimSpace(symbologyIDPattern.ReplaceAllString(raw, ""))
+	code = strings.TrimPrefix(code, groupSeparator)
+	if code == "" {
+		return MarkCode{}, fmt.Errorf("%w: empty code", ErrInvalidMarkCode)
+	}
+
+	m := MarkCode{Raw: code}
+
+	switch {
+	case strings.HasPrefix(code, "01") && len(code) > 16 && !shortPattern.MatchString(code):
+		return parseGS1(m)
+	case digitsPattern.MatchString(code) && (len(code) == 8 || len(code) == 13 || len(code) == 14):
+		if !validCheckDigit(code) {
+			return MarkCode{}, fmt.Errorf("%w: wrong check digit of %q", ErrInvalidMarkCode, code)
+		}
+		m.GTIN = code
+		m.Format = map[int]string{8: MarkCodeFormatEan8, 13: MarkCodeFormatEan13, 14: MarkCodeFormatItf14}[len(code)]
+	case shortPattern.MatchString(code):
+		m.Format = MarkCodeFormatShort
+		m.GTIN = code[:14]
+		m.Serial = code[14:21]
+	case furPattern.MatchString(code):
+		m.Format = MarkCodeFormatFur
+	case len(code) == 68 && egaisPattern.MatchString(code):
+		m.Format = MarkCodeFormatEgais20
+	case len(code) == 150 && egaisPattern.MatchString(code):
+		m.Format = MarkCodeFormatEgais30
+	default:
+		m.Format = MarkCodeFormatUnknown
+	}
+
+	return m, nil
+}
+
+// parseGS1 splits the GS1 code into application identifiers. Codes with a crypto tail
+// (identifiers 91, 92 or 93) are GS1.M, others are GS1.0.
+func parseGS1(m MarkCode) (MarkCode, error) {
+	m.Fields = make(map[string]string)
+
+	rest := m.Raw
+	for rest != "" {
+		rest = strings.TrimPrefix(rest, groupSeparator)
+
+		ai, data, tail, err := nextGS1Field(rest)
+		if err != nil {
+			return MarkCode{}, err
+		}
+		if _, ok := m.Fields[ai]; ok {
+			return MarkCode{}, fmt.Errorf("%w: duplicate application identifier %s", ErrInvalidMarkCode, ai)
+		}
+		m.Fields[ai] = data
+		rest = tail
+	}
+
+	m.GTIN = m.Fields["01"]
+	m.Serial = m.Fields["21"]
+	if !validCheckDigit(m.GTIN) {
+		return MarkCode{}, fmt.Errorf("%w: wrong check digit of GTIN %q", ErrInvalidMarkCode, m.GTIN)
+	}
+
+	m.Format = MarkCodeFormatGs10
+	for _, ai := range []string{"91", "92", "93"} {
+		if _, ok := m.Fields[ai]; ok {
+			m.Format = MarkCodeFormatGs1m
+			if m.Serial == "" {
+				return MarkCode{}, fmt.Errorf("%w: serial number (21) is missing", ErrInvalidMarkCode)
+			}
+			break
+		}
+	}
+
+	return m, nil
+}
+
+func nextGS1Field(code string) (ai, data, rest string, err error) {
+	for ai, length := range gs1FixedLengths {
+		if strings.HasPrefix(code, ai) {
+			if len(code) < len(ai)+length {
+				return "", "", "", fmt.Errorf("%w: application identifier %s is truncated", ErrInvalidMarkCode, ai)
+			}
+			data = code[len(ai) : len(ai)+length]
+			if !digitsPattern.MatchString(data) {
+				return "", "", "", fmt.Errorf("%w: application identifier %s must be numeric", ErrInvalidMarkCode, ai)
+			}
+
+			return ai, data, code[len(ai)+length:], nil
+		}
+	}
+
+	for _, ai := range gs1VariableIdentifiers {
+		if strings.HasPrefix(code, ai) {
+			data, rest, _ = strings.Cut(code[len(ai):], groupSeparator)
+			if data == "" {
+				return "", "", "", fmt.Errorf("%w: application identifier %s is empty", ErrInvalidMarkCode, ai)
+			}
+
+			return ai, data, rest, nil
+		}
+	}
+
+	return "", "", "", fmt.Errorf("%w: unknown application identifier at %q", ErrInvalidMarkCode, code)
+}
+
+// validCheckDigit validates the GS1 check digit of EAN-8, EAN-13, ITF-14 and GTIN.
+func validCheckDigit(code string) bool {
+	if len(code) < 2 || !digitsPattern.MatchString(code) {
+		return false
+	}
+
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		d := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
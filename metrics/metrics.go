@@ -0,0 +1,160 @@
+// Package metrics provides Prometheus metrics for the YooKassa client and webhooks.
+// It is a separate module, so the client does not depend on Prometheus.
+//
+//	collector := metrics.NewCollector("shop")
+//	prometheus.MustRegister(collector)
+//
+//	client := yookassa.NewConfig(shopID, apiKey, yookassa.WithRequestHook(collector.RequestHook()))
+//	handler := yookassa.NewWebhookHandler(collector.WrapNotificationHandler(handle))
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	yookassa "github.com/flew1x/yookassa_api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes of the requests.
+const (
+	OutcomeSuccess      = "success"
+	OutcomeClientError  = "client_error"
+	OutcomeServerError  = "server_error"
+	OutcomeRateLimited  = "rate_limited"
+	OutcomeNetworkError = "network_error"
+)
+
+// Collector collects metrics of the YooKassa client. It implements prometheus.Collector.
+type Collector struct {
+	requests        *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	rateLimited     *prometheus.CounterVec
+	notifications   *prometheus.CounterVec
+	handlerFailures *prometheus.CounterVec
+	receipts        *prometheus.CounterVec
+}
+
+// NewCollector creates a collector with metrics prefixed by namespace and "yookassa".
+func NewCollector(namespace string) *Collector {
+	const subsystem = "yookassa"
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests to the YooKassa API by operation and outcome.",
+		}, []string{"operation", "outcome"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests to the YooKassa API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_total",
+			Help:      "Number of retried HTTP requests to the YooKassa API.",
+		}, []string{"operation"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rate_limited_total",
+			Help:      "Number of responses with status 429 from the YooKassa API.",
+		}, []string{"operation"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "notifications_total",
+			Help:      "Number of received webhook notifications by event.",
+		}, []string{"event"}),
+		handlerFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "notification_failures_total",
+			Help:      "Number of webhook notifications failed to be processed by event.",
+		}, []string{"event"}),
+		receipts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "receipt_registrations_total",
+			Help:      "Number of finished receipt registrations by status.",
+		}, []string{"status"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.notifications.Describe(ch)
+	c.handlerFailures.Describe(ch)
+	c.receipts.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.notifications.Collect(ch)
+	c.handlerFailures.Collect(ch)
+	c.receipts.Collect(ch)
+}
+
+// RequestHook returns a hook for yookassa.WithRequestHook which observes every HTTP attempt.
+func (c *Collector) RequestHook() func(ctx context.Context, info yookassa.RequestInfo) {
+	return func(_ context.Context, info yookassa.RequestInfo) {
+		c.requests.WithLabelValues(info.Operation, outcome(info)).Inc()
+		c.latency.WithLabelValues(info.Operation).Observe(info.Duration.Seconds())
+
+		if info.Attempt > 1 {
+			c.retries.WithLabelValues(info.Operation).Inc()
+		}
+		if info.StatusCode == http.StatusTooManyRequests {
+			c.rateLimited.WithLabelValues(info.Operation).Inc()
+		}
+	}
+}
+
+// WrapNotificationHandler counts notifications passed to handle and its failures.
+func (c *Collector) WrapNotificationHandler(handle yookassa.NotificationHandlerFunc) yookassa.NotificationHandlerFunc {
+	return func(ctx context.Context, notification yookassa.Notification) error {
+		c.notifications.WithLabelValues(notification.Event).Inc()
+
+		err := handle(ctx, notification)
+		if err != nil {
+			c.handlerFailures.WithLabelValues(notification.Event).Inc()
+		}
+
+		return err
+	}
+}
+
+// ObserveReceiptRegistration counts the finished receipt registration. Set it as
+// yookassa.ReceiptTrackerConfig.Observe to count the results of the tracker.
+func (c *Collector) ObserveReceiptRegistration(status yookassa.ReceiptRegistrationStatus) {
+	c.receipts.WithLabelValues(string(status)).Inc()
+}
+
+func outcome(info yookassa.RequestInfo) string {
+	switch {
+	case info.StatusCode == 0 && info.Err != nil:
+		return OutcomeNetworkError
+	case info.StatusCode == http.StatusTooManyRequests:
+		return OutcomeRateLimited
+	case info.StatusCode >= http.StatusInternalServerError:
+		return OutcomeServerError
+	case info.StatusCode >= http.StatusBadRequest:
+		return OutcomeClientError
+	default:
+		return OutcomeSuccess
+	}
+}
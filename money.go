@@ -0,0 +1,96 @@
+package yookassa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QuantityScale is the number of thousandths in one unit of the quantity.
+// Quantities are kept as integer thousandths to avoid floating point errors.
+const QuantityScale = 1000
+
+// ParseMinorUnits parses the amount value like "10.00" into minor units (kopecks).
+func ParseMinorUnits(value string) (int64, error) {
+	return parseFixed(value, 2)
+}
+
+// FormatMinorUnits formats minor units (kopecks) as the amount value like "10.00".
+func FormatMinorUnits(minor int64) string {
+	return formatFixed(minor, 2)
+}
+
+// ParseQuantity parses the quantity like "1.5" into thousandths.
+func ParseQuantity(value string) (int64, error) {
+	return parseFixed(value, 3)
+}
+
+// FormatQuantity formats thousandths as the quantity, trimming trailing zeros: 1500 is "1.5".
+func FormatQuantity(thousandths int64) string {
+	s := formatFixed(thousandths, 3)
+	s = strings.TrimRight(s, "0")
+
+	return strings.TrimSuffix(s, ".")
+}
+
+// NewAmount creates the amount from minor units.
+func NewAmount(minor int64, currency string) Amount {
+	return Amount{Value: FormatMinorUnits(minor), Currency: currency}
+}
+
+// MinorUnits returns the value of the amount in minor units.
+func (a Amount) MinorUnits() (int64, error) {
+	return ParseMinorUnits(a.Value)
+}
+
+// MultiplyQuantity returns the cost of the quantity in thousandths at the price in minor units,
+// rounded half up to a minor unit.
+func MultiplyQuantity(price, quantity int64) int64 {
+	return divRound(price*quantity, QuantityScale)
+}
+
+// divRound divides rounding half away from zero.
+func divRound(a, b int64) int64 {
+	if (a < 0) != (b < 0) {
+		return -((-a + b/2) / b)
+	}
+
+	return (a + b/2) / b
+}
+
+func parseFixed(value string, decimals int) (int64, error) {
+	s := strings.TrimSpace(value)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > decimals {
+		return 0, fmt.Errorf("invalid decimal %q: at most %d digits after the point are allowed", value, decimals)
+	}
+	fraction += strings.Repeat("0", decimals-len(fraction))
+
+	n, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid decimal %q", value)
+	}
+	if negative {
+		n = -n
+	}
+
+	return n, nil
+}
+
+func formatFixed(n int64, decimals int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	s := strconv.FormatInt(n, 10)
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+}
@@ -0,0 +1,299 @@
+package yookassa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NotificationStore remembers processed notifications and the latest status of their objects.
+type NotificationStore interface {
+	// IsProcessed reports whether the event of the object was already processed.
+	IsProcessed(ctx context.Context, objectID, event string) (bool, error)
+	// CurrentStatus returns the status with the highest precedence stored for the object or "".
+	CurrentStatus(ctx context.Context, objectID string) (PaymentStatus, error)
+	// MarkProcessed records the event of the object with the status of the object.
+	MarkProcessed(ctx context.Context, objectID, event string, status PaymentStatus) error
+}
+
+// NotificationProcessor skips notifications which were already processed for the object ID and
+// event and late notifications whose status has lower precedence than the stored one, so
+// a late payment.waiting_for_capture never overwrites payment.succeeded.
+//
+// A notification is marked processed only after the handler succeeds, so failed notifications
+// are handled again when YooKassa redelivers them. Notifications of one object are serialized
+// within the process, so a single instance handles each of them once. Replicas sharing
+// SQLNotificationStore are not serialized: two of them may receive the same notification,
+// both see it unprocessed and both run the handler. Across replicas the handling is
+// at least once, so the handler must be idempotent, as example by updating the order only
+// from the status it expects. Use Handle with NewWebhookHandler.
+type NotificationProcessor struct {
+	store  NotificationStore
+	handle NotificationHandlerFunc
+	locks  keyedMutex
+}
+
+// NewNotificationProcessor creates a processor which passes new notifications to handle.
+func NewNotificationProcessor(store NotificationStore, handle NotificationHandlerFunc) *NotificationProcessor {
+	return &NotificationProcessor{store: store, handle: handle}
+}
+
+// Handle processes the notification. It implements NotificationHandlerFunc.
+func (p *NotificationProcessor) Handle(ctx context.Context, notification Notification) error {
+	var object struct {
+		ID     string        `json:"id"`
+		Status PaymentStatus `json:"status"`
+	}
+	if err := json.Unmarshal(notification.Object, &object); err != nil {
+		return fmt.Errorf("failed to unmarshal notification object: %w", err)
+	}
+
+	unlock := p.locks.lock(object.ID)
+	defer unlock()
+
+	processed, err := p.store.IsProcessed(ctx, object.ID, notification.Event)
+	if err != nil {
+		return fmt.Errorf("failed to check notification: %w", err)
+	}
+	if processed {
+		return nil
+	}
+
+	current, err := p.store.CurrentStatus(ctx, object.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get current status: %w", err)
+	}
+
+	if current == "" || current.Precedence() < object.Status.Precedence() || current == object.Status {
+		if err = p.handle(ctx, notification); err != nil {
+			return err
+		}
+	}
+
+	if err = p.store.MarkProcessed(ctx, object.ID, notification.Event, object.Status); err != nil {
+		return fmt.Errorf("failed to mark notification processed: %w", err)
+	}
+
+	return nil
+}
+
+// keyedMutex serializes work on the same key.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (m *keyedMutex) lock(key string) (unlock func()) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		m.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// DefaultNotificationTTL is the time MemoryNotificationStore remembers an object by default.
+// YooKassa stops redelivering a notification 24 hours after the event.
+const DefaultNotificationTTL = 48 * time.Hour
+
+// MemoryNotificationStore keeps processed notifications in memory.
+// It suits tests and single instance services which tolerate duplicates after restart.
+// Objects are forgotten when no notification of them arrived within the TTL, so the memory
+// stays bounded by the rate of notifications.
+type MemoryNotificationStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	objects   map[string]*memoryNotificationObject
+	lastSweep time.Time
+}
+
+type memoryNotificationObject struct {
+	events    map[string]bool
+	status    PaymentStatus
+	updatedAt time.Time
+}
+
+// NewMemoryNotificationStore creates an empty store which forgets objects after the ttl.
+// Zero ttl means DefaultNotificationTTL.
+func NewMemoryNotificationStore(ttl time.Duration) *MemoryNotificationStore {
+	if ttl <= 0 {
+		ttl = DefaultNotificationTTL
+	}
+
+	return &MemoryNotificationStore{
+		ttl:       ttl,
+		objects:   make(map[string]*memoryNotificationObject),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryNotificationStore) IsProcessed(_ context.Context, objectID, event string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object := s.object(objectID, time.Now())
+
+	return object != nil && object.events[event], nil
+}
+
+func (s *MemoryNotificationStore) CurrentStatus(_ context.Context, objectID string) (PaymentStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object := s.object(objectID, time.Now())
+	if object == nil {
+		return "", nil
+	}
+
+	return object.status, nil
+}
+
+func (s *MemoryNotificationStore) MarkProcessed(_ context.Context, objectID, event string, status PaymentStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	object := s.object(objectID, now)
+	if object == nil {
+		object = &memoryNotificationObject{events: make(map[string]bool)}
+		s.objects[objectID] = object
+	}
+	object.events[event] = true
+	object.updatedAt = now
+
+	if object.status == "" || object.status.Precedence() < status.Precedence() {
+		object.status = status
+	}
+
+	return nil
+}
+
+// object returns the object unless it expired.
+func (s *MemoryNotificationStore) object(objectID string, now time.Time) *memoryNotificationObject {
+	object, ok := s.objects[objectID]
+	if !ok || now.Sub(object.updatedAt) >= s.ttl {
+		return nil
+	}
+
+	return object
+}
+
+// sweep removes the expired objects. It walks the map at most once per half of the TTL.
+func (s *MemoryNotificationStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl/2 {
+		return
+	}
+	s.lastSweep = now
+
+	for id, object := range s.objects {
+		if now.Sub(object.updatedAt) >= s.ttl {
+			delete(s.objects, id)
+		}
+	}
+}
+
+// SQLNotificationStore keeps processed notifications in a database table, so duplicates are
+// detected across restarts and replicas. Concurrent duplicates on different replicas may both
+// be handled, see NotificationProcessor. Create the table with Migrate.
+type SQLNotificationStore struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+}
+
+// NewSQLNotificationStore creates a store in the table. The table name is not escaped.
+func NewSQLNotificationStore(db *sql.DB, table string, placeholder SQLPlaceholder) *SQLNotificationStore {
+	return &SQLNotificationStore{db: db, table: table, placeholder: placeholder}
+}
+
+// Migrate creates the table if it does not exist.
+func (s *SQLNotificationStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	object_id VARCHAR(64) NOT NULL,
+	event VARCHAR(64) NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	processed_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (object_id, event)
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create notifications table: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLNotificationStore) IsProcessed(ctx context.Context, objectID, event string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		s.placeholder.rebind(`SELECT COUNT(*) FROM `+s.table+` WHERE object_id = ? AND event = ?`),
+		objectID, event,
+	).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (s *SQLNotificationStore) CurrentStatus(ctx context.Context, objectID string) (PaymentStatus, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.placeholder.rebind(`SELECT status FROM `+s.table+` WHERE object_id = ?`),
+		objectID,
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var current PaymentStatus
+	for rows.Next() {
+		var status PaymentStatus
+		if err = rows.Scan(&status); err != nil {
+			return "", err
+		}
+		if current == "" || current.Precedence() < status.Precedence() {
+			current = status
+		}
+	}
+
+	return current, rows.Err()
+}
+
+// MarkProcessed inserts the record. A concurrent insert of the same event by another replica
+// fails with the unique violation after both handled it, and the redelivery is skipped.
+func (s *SQLNotificationStore) MarkProcessed(ctx context.Context, objectID, event string, status PaymentStatus) error {
+	_, err := s.db.ExecContext(ctx,
+		s.placeholder.rebind(`INSERT INTO `+s.table+` (object_id, event, status, processed_at) VALUES (?, ?, ?, ?)`),
+		objectID, event, string(status), time.Now().UTC(),
+	)
+
+	return err
+}
@@ -0,0 +1,257 @@
+package yookassa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrQueueClosed is returned by NotificationQueue.Pop when the queue is closed and empty,
+// and by NotificationQueue.Push after the queue is closed.
+var ErrQueueClosed = errors.New("notification queue is closed")
+
+// NotificationQueue holds notifications accepted from YooKassa until they are handled.
+//
+// A durable queue keeps the notification from Push until Ack, so notifications accepted
+// before a crash are handled after the restart.
+type NotificationQueue interface {
+	// Push stores the notification. It returns ErrQueueFull if there is no room.
+	Push(ctx context.Context, notification Notification) error
+	// Pop blocks until a notification is available, the ctx is done or the queue is closed.
+	// It returns ErrQueueClosed once the queue is closed and has nothing more to pop for this process.
+	Pop(ctx context.Context) (QueuedNotification, error)
+	// Ack removes the handled or dead-lettered notification from the queue.
+	Ack(ctx context.Context, id string) error
+	// Close stops accepting notifications. A memory queue can still be popped until it is empty,
+	// a durable queue stops popping and leaves the notifications to other replicas or the next start.
+	Close() error
+	// Durable reports whether a popped notification which is not acknowledged is popped again,
+	// as example after its lease expires.
+	Durable() bool
+}
+
+// QueuedNotification is a notification popped from the NotificationQueue.
+type QueuedNotification struct {
+	ID           string // ID of the notification in the queue, passed to Ack
+	Notification Notification
+}
+
+// MemoryNotificationQueue keeps notifications in a buffered channel.
+// Notifications are lost if the process crashes before they are handled.
+type MemoryNotificationQueue struct {
+	mu     sync.RWMutex
+	closed bool
+	queue  chan QueuedNotification
+}
+
+// NewMemoryNotificationQueue creates a queue with the capacity of size notifications.
+func NewMemoryNotificationQueue(size int) *MemoryNotificationQueue {
+	return &MemoryNotificationQueue{queue: make(chan QueuedNotification, size)}
+}
+
+// Push puts the notification into the queue without waiting.
+func (q *MemoryNotificationQueue) Push(_ context.Context, notification Notification) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.queue <- QueuedNotification{Notification: notification}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *MemoryNotificationQueue) Pop(ctx context.Context) (QueuedNotification, error) {
+	select {
+	case item, ok := <-q.queue:
+		if !ok {
+			return QueuedNotification{}, ErrQueueClosed
+		}
+		return item, nil
+	case <-ctx.Done():
+		return QueuedNotification{}, ctx.Err()
+	}
+}
+
+// Ack does nothing, popped notifications are already removed from the channel.
+func (q *MemoryNotificationQueue) Ack(context.Context, string) error {
+	return nil
+}
+
+// Durable reports false, popped notifications exist only in the memory of the worker.
+func (q *MemoryNotificationQueue) Durable() bool {
+	return false
+}
+
+func (q *MemoryNotificationQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+
+	return nil
+}
+
+// sqlQueuePollInterval is the delay between polls of the empty SQLNotificationQueue.
+const sqlQueuePollInterval = time.Second
+
+// SQLNotificationQueue keeps notifications in a database table, so notifications accepted from
+// YooKassa survive a crash. A popped notification is leased for the lease time and popped again
+// if it is not acknowledged, as example after a crash. Several replicas may share the table.
+type SQLNotificationQueue struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+	lease       time.Duration
+	closed      atomic.Bool
+}
+
+// NewSQLNotificationQueue creates a queue in the table. The table name is not escaped.
+// Zero lease means 5 minutes. It must exceed the time to handle a notification with all retries.
+func NewSQLNotificationQueue(db *sql.DB, table string, placeholder SQLPlaceholder, lease time.Duration) *SQLNotificationQueue {
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+
+	return &SQLNotificationQueue{db: db, table: table, placeholder: placeholder, lease: lease}
+}
+
+// Migrate creates the table if it does not exist.
+func (q *SQLNotificationQueue) Migrate(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+q.table+` (
+	id VARCHAR(64) NOT NULL PRIMARY KEY,
+	event VARCHAR(64) NOT NULL,
+	object TEXT NOT NULL,
+	deliveries INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	locked_until TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create notification queue table: %w", err)
+	}
+
+	return nil
+}
+
+// Push inserts the notification. The queue has no capacity limit.
+func (q *SQLNotificationQueue) Push(ctx context.Context, notification Notification) error {
+	if q.closed.Load() {
+		return ErrQueueClosed
+	}
+
+	now := time.Now().UTC()
+	_, err := q.db.ExecContext(ctx,
+		q.placeholder.rebind(`INSERT INTO `+q.table+` (id, event, object, deliveries, created_at, locked_until) VALUES (?, ?, ?, 0, ?, ?)`),
+		uuid.NewV4().String(), notification.Event, string(notification.Object), now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert notification: %w", err)
+	}
+
+	return nil
+}
+
+// Pop leases the oldest notification which is not leased by another worker.
+// After Close it returns ErrQueueClosed at once, the table is shared with other replicas.
+func (q *SQLNotificationQueue) Pop(ctx context.Context) (QueuedNotification, error) {
+	for {
+		if q.closed.Load() {
+			return QueuedNotification{}, ErrQueueClosed
+		}
+
+		item, ok, err := q.claim(ctx)
+		if err != nil || ok {
+			return item, err
+		}
+
+		timer := time.NewTimer(sqlQueuePollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return QueuedNotification{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// claim leases the oldest available notification. It reports false if the queue is empty.
+func (q *SQLNotificationQueue) claim(ctx context.Context) (QueuedNotification, bool, error) {
+	for {
+		now := time.Now().UTC()
+
+		var (
+			item       QueuedNotification
+			object     string
+			deliveries int
+		)
+		err := q.db.QueryRowContext(ctx,
+			q.placeholder.rebind(`SELECT id, event, object, deliveries FROM `+q.table+` WHERE locked_until <= ? ORDER BY created_at LIMIT 1`),
+			now,
+		).Scan(&item.ID, &item.Notification.Event, &object, &deliveries)
+		if errors.Is(err, sql.ErrNoRows) {
+			return QueuedNotification{}, false, nil
+		}
+		if err != nil {
+			return QueuedNotification{}, false, fmt.Errorf("failed to select notification: %w", err)
+		}
+
+		// The number of deliveries guards the lease from the concurrent claims of other workers.
+		res, err := q.db.ExecContext(ctx,
+			q.placeholder.rebind(`UPDATE `+q.table+` SET deliveries = deliveries + 1, locked_until = ? WHERE id = ? AND deliveries = ?`),
+			now.Add(q.lease), item.ID, deliveries,
+		)
+		if err != nil {
+			return QueuedNotification{}, false, fmt.Errorf("failed to lease notification: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return QueuedNotification{}, false, fmt.Errorf("failed to lease notification: %w", err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		item.Notification.Type = "notification"
+		item.Notification.Object = json.RawMessage(object)
+
+		return item, true, nil
+	}
+}
+
+// Ack deletes the notification.
+func (q *SQLNotificationQueue) Ack(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, q.placeholder.rebind(`DELETE FROM `+q.table+` WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+
+	return nil
+}
+
+// Durable reports true, leased notifications are popped again when the lease expires.
+func (q *SQLNotificationQueue) Durable() bool {
+	return true
+}
+
+// Close stops accepting and popping notifications. Notifications left in the table are popped
+// by other replicas or after the restart.
+func (q *SQLNotificationQueue) Close() error {
+	q.closed.Store(true)
+
+	return nil
+}
@@ -0,0 +1,287 @@
+// Package otel provides OpenTelemetry tracing for the YooKassa client.
+// It is a separate module, so the client does not depend on OpenTelemetry.
+//
+// Wrap the client with NewClient and register RequestHook on it, so spans
+// are created for every operation and every HTTP attempt is recorded:
+//
+//	client := yookassa.NewConfig(shopID, apiKey, yookassa.WithRequestHook(otel.RequestHook()))
+//	traced := otel.NewClient(client)
+package otel
+
+import (
+	"context"
+	"encoding/json"
+
+	yookassa "github.com/flew1x/yookassa_api"
+	otelglobal "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/flew1x/yookassa_api/otel"
+	spanPrefix          = "yookassa."
+)
+
+// Attribute keys set on the spans.
+const (
+	AttrOperation      = attribute.Key("yookassa.operation")
+	AttrPaymentID      = attribute.Key("yookassa.payment.id")
+	AttrPaymentStatus  = attribute.Key("yookassa.payment.status")
+	AttrAmountCurrency = attribute.Key("yookassa.amount.currency")
+	AttrHTTPStatus     = attribute.Key("http.response.status_code")
+	AttrAttempt        = attribute.Key("yookassa.attempt")
+	AttrRetryAttempts  = attribute.Key("yookassa.retry_attempts")
+	AttrErrorCode      = attribute.Key("yookassa.error.code")
+	AttrErrorID        = attribute.Key("yookassa.error.id")
+	AttrReceiptID      = attribute.Key("yookassa.receipt.id")
+	AttrReceiptType    = attribute.Key("yookassa.receipt.type")
+	AttrReceiptStatus  = attribute.Key("yookassa.receipt.status")
+	AttrReceiptCount   = attribute.Key("yookassa.receipt.count")
+	AttrRefundID       = attribute.Key("yookassa.refund.id")
+	AttrRefundStatus   = attribute.Key("yookassa.refund.status")
+)
+
+// PaymentClient is the part of the YooKassa client which works with payments.
+type PaymentClient interface {
+	SendPaymentRequest(ctx context.Context, paymentRequest yookassa.PaymentRequest) (yookassa.PaymentResponse, error)
+	SendRawPaymentRequest(ctx context.Context, paymentRequest json.RawMessage) (yookassa.PaymentResponse, error)
+	GetPayment(ctx context.Context, paymentID string) (yookassa.PaymentResponse, error)
+}
+
+// ReceiptClient is the part of the YooKassa client which works with receipts.
+type ReceiptClient interface {
+	SendReceiptRequest(ctx context.Context, receiptRequest yookassa.ReceiptRequest) (yookassa.ReceiptResponse, error)
+	GetReceipt(ctx context.Context, receiptID string) (yookassa.ReceiptResponse, error)
+	ListReceipts(ctx context.Context, paymentID string) ([]yookassa.ReceiptResponse, error)
+}
+
+// RefundClient is the part of the YooKassa client which works with refunds.
+type RefundClient interface {
+	GetRefund(ctx context.Context, refundID string) (yookassa.RefundResponse, error)
+}
+
+// API is the traced YooKassa client.
+type API interface {
+	PaymentClient
+	ReceiptClient
+	RefundClient
+}
+
+// Client creates a span for every operation of the wrapped client.
+type Client struct {
+	next   API
+	tracer trace.Tracer
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+}
+
+// Option configures the tracing.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. The global one is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{tracerProvider: otelglobal.GetTracerProvider()}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// NewClient wraps the client with tracing.
+func NewClient(next API, opts ...Option) *Client {
+	c := newConfig(opts)
+
+	return &Client{
+		next:   next,
+		tracer: c.tracerProvider.Tracer(instrumentationName),
+	}
+}
+
+// SendPaymentRequest creates the payment within the "yookassa.payments.create" span.
+func (c *Client) SendPaymentRequest(ctx context.Context, paymentRequest yookassa.PaymentRequest) (yookassa.PaymentResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationCreatePayment,
+		AttrAmountCurrency.String(paymentRequest.Amount.Currency),
+	)
+	defer span.End()
+
+	resp, err := c.next.SendPaymentRequest(ctx, paymentRequest)
+	endPayment(span, resp, err)
+
+	return resp, err
+}
+
+// SendRawPaymentRequest creates the payment from the encoded request within the "yookassa.payments.create" span.
+func (c *Client) SendRawPaymentRequest(ctx context.Context, paymentRequest json.RawMessage) (yookassa.PaymentResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationCreatePayment)
+	defer span.End()
+
+	resp, err := c.next.SendRawPaymentRequest(ctx, paymentRequest)
+	endPayment(span, resp, err)
+
+	return resp, err
+}
+
+// GetPayment retrieves the payment within the "yookassa.payments.get" span.
+func (c *Client) GetPayment(ctx context.Context, paymentID string) (yookassa.PaymentResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationGetPayment, AttrPaymentID.String(paymentID))
+	defer span.End()
+
+	resp, err := c.next.GetPayment(ctx, paymentID)
+	endPayment(span, resp, err)
+
+	return resp, err
+}
+
+// SendReceiptRequest creates the receipt within the "yookassa.receipts.create" span.
+func (c *Client) SendReceiptRequest(ctx context.Context, receiptRequest yookassa.ReceiptRequest) (yookassa.ReceiptResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationCreateReceipt,
+		AttrReceiptType.String(receiptRequest.Type),
+		AttrPaymentID.String(receiptRequest.PaymentID),
+	)
+	defer span.End()
+
+	resp, err := c.next.SendReceiptRequest(ctx, receiptRequest)
+	endReceipt(span, resp, err)
+
+	return resp, err
+}
+
+// GetReceipt retrieves the receipt within the "yookassa.receipts.get" span.
+func (c *Client) GetReceipt(ctx context.Context, receiptID string) (yookassa.ReceiptResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationGetReceipt, AttrReceiptID.String(receiptID))
+	defer span.End()
+
+	resp, err := c.next.GetReceipt(ctx, receiptID)
+	endReceipt(span, resp, err)
+
+	return resp, err
+}
+
+// ListReceipts retrieves the receipts of the payment within the "yookassa.receipts.list" span.
+// All pages are requested within the same span.
+func (c *Client) ListReceipts(ctx context.Context, paymentID string) ([]yookassa.ReceiptResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationListReceipts, AttrPaymentID.String(paymentID))
+	defer span.End()
+
+	receipts, err := c.next.ListReceipts(ctx, paymentID)
+	if err != nil {
+		recordError(span, err)
+		return receipts, err
+	}
+
+	span.SetAttributes(AttrReceiptCount.Int(len(receipts)))
+	span.SetStatus(codes.Ok, "")
+
+	return receipts, nil
+}
+
+// GetRefund retrieves the refund within the "yookassa.refunds.get" span.
+func (c *Client) GetRefund(ctx context.Context, refundID string) (yookassa.RefundResponse, error) {
+	ctx, span := c.start(ctx, yookassa.OperationGetRefund, AttrRefundID.String(refundID))
+	defer span.End()
+
+	resp, err := c.next.GetRefund(ctx, refundID)
+	if err != nil {
+		recordError(span, err)
+		return resp, err
+	}
+
+	span.SetAttributes(
+		AttrPaymentID.String(resp.PaymentID),
+		AttrRefundStatus.String(string(resp.Status)),
+		AttrAmountCurrency.String(resp.Amount.Currency),
+	)
+	span.SetStatus(codes.Ok, "")
+
+	return resp, nil
+}
+
+func (c *Client) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, AttrOperation.String(operation))
+
+	return c.tracer.Start(ctx, spanPrefix+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endPayment sets the status of the span from the result of the whole operation.
+// Failed attempts which were retried successfully do not fail the span.
+func endPayment(span trace.Span, resp yookassa.PaymentResponse, err error) {
+	if err != nil {
+		recordError(span, err)
+		return
+	}
+
+	span.SetAttributes(
+		AttrPaymentID.String(resp.ID),
+		AttrPaymentStatus.String(string(resp.Status)),
+		AttrAmountCurrency.String(resp.Amount.Currency),
+	)
+	span.SetStatus(codes.Ok, "")
+}
+
+func endReceipt(span trace.Span, resp yookassa.ReceiptResponse, err error) {
+	if err != nil {
+		recordError(span, err)
+		return
+	}
+
+	span.SetAttributes(
+		AttrReceiptID.String(resp.ID),
+		AttrReceiptType.String(resp.Type),
+		AttrReceiptStatus.String(string(resp.Status)),
+	)
+	span.SetStatus(codes.Ok, "")
+}
+
+func recordError(span trace.Span, err error) {
+	if apiErr, ok := yookassa.AsAPIError(err); ok {
+		span.SetAttributes(
+			AttrErrorCode.String(apiErr.Code),
+			AttrErrorID.String(apiErr.ID),
+		)
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// RequestHook returns a hook for yookassa.WithRequestHook which records every HTTP attempt
+// as an event of the span started by Client and sets the HTTP status and retry count.
+// The status of the span is set by Client from the result of the whole operation.
+func RequestHook() func(ctx context.Context, info yookassa.RequestInfo) {
+	return func(ctx context.Context, info yookassa.RequestInfo) {
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return
+		}
+
+		attrs := []attribute.KeyValue{
+			AttrAttempt.Int(info.Attempt),
+			attribute.String("http.request.method", info.Method),
+			attribute.Int64("yookassa.duration_ms", info.Duration.Milliseconds()),
+		}
+		if info.StatusCode != 0 {
+			attrs = append(attrs, AttrHTTPStatus.Int(info.StatusCode))
+			span.SetAttributes(AttrHTTPStatus.Int(info.StatusCode))
+		}
+		if info.Err != nil {
+			attrs = append(attrs, attribute.String("error.message", info.Err.Error()))
+		}
+
+		span.AddEvent("http.attempt", trace.WithAttributes(attrs...))
+		span.SetAttributes(AttrRetryAttempts.Int(info.Attempt - 1))
+	}
+}
@@ -0,0 +1,281 @@
+package yookassa
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Statuses of the outbox entries.
+const (
+	OutboxPending = "pending" // The payment is not created yet
+	OutboxSent    = "sent"    // The payment is created, the response is stored
+	OutboxFailed  = "failed"  // The API rejected the request with a 4xx error
+	OutboxExpired = "expired" // The Idempotence-Key expired before the result was stored, reconcile the payment
+)
+
+var (
+	// ErrOutboxEntryNotFound is returned by Outbox.Get for unknown keys.
+	ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+	// ErrCardDataNotAllowed is returned by Outbox.Enqueue for requests with card data,
+	// which must never be stored. Pay with payment_token or payment_method_id instead.
+	ErrCardDataNotAllowed = errors.New("card data is not allowed in the outbox")
+)
+
+// PaymentSender creates payments. It is implemented by the client.
+type PaymentSender interface {
+	SendPaymentRequest(ctx context.Context, paymentRequest PaymentRequest) (PaymentResponse, error)
+}
+
+// RawPaymentSender creates payments from encoded requests. It is implemented by the client.
+type RawPaymentSender interface {
+	SendRawPaymentRequest(ctx context.Context, paymentRequest json.RawMessage) (PaymentResponse, error)
+}
+
+// OutboxEntry is a payment request recorded in the outbox.
+type OutboxEntry struct {
+	IdempotenceKey string           // Key sent with every attempt to create the payment
+	Reference      string           // Reference of the caller, as example order ID
+	Status         string           // OutboxPending, OutboxSent, OutboxFailed or OutboxExpired
+	Request        json.RawMessage  // Recorded request, sent as is
+	Response       *PaymentResponse // Response of the API when the status is OutboxSent
+	Error          string           // Last error of the dispatching
+	Attempts       int              // Number of dispatching attempts
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Outbox records payment requests in the caller's transaction and creates the payments later,
+// so a crash between creating the payment and saving its ID never loses a real payment.
+//
+// Every entry is sent with the same Idempotence-Key, so resuming after a restart or running
+// the dispatcher on several replicas never creates a second payment.
+// The key is honored by YooKassa for IdempotenceKeyLifetime only. Entries which are still pending
+// near its end are never sent again, as the payment may have been created by a request whose
+// response was lost. Dispatch moves them to OutboxExpired: find the payment by the reference,
+// as example in the metadata, and enqueue the request again only if it does not exist.
+type Outbox struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+	sender      RawPaymentSender
+}
+
+// NewOutbox creates an outbox in the table. The table name is not escaped. Create the table with Migrate.
+func NewOutbox(db *sql.DB, table string, placeholder SQLPlaceholder, sender RawPaymentSender) *Outbox {
+	return &Outbox{db: db, table: table, placeholder: placeholder, sender: sender}
+}
+
+// Migrate creates the table if it does not exist.
+func (o *Outbox) Migrate(ctx context.Context) error {
+	_, err := o.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+o.table+` (
+	idempotence_key VARCHAR(64) NOT NULL PRIMARY KEY,
+	reference VARCHAR(255) NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	request TEXT NOT NULL,
+	response TEXT,
+	error TEXT,
+	attempts INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+
+	return nil
+}
+
+// Enqueue records the request in the transaction and returns its idempotence key.
+// The payment is created by Dispatch after the transaction is committed.
+//
+// Requests with card data fail with ErrCardDataNotAllowed, as PCI DSS forbids storing the card
+// number and CSC. Use payment_token from Checkout.js or the mobile SDK, or payment_method_id.
+func (o *Outbox) Enqueue(ctx context.Context, tx *sql.Tx, reference string, paymentRequest PaymentRequest) (string, error) {
+	request, err := json.Marshal(paymentRequest)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payment request: %w", err)
+	}
+	if hasCardData(request) {
+		return "", ErrCardDataNotAllowed
+	}
+
+	key := uuid.NewV4().String()
+	now := time.Now().UTC()
+
+	_, err = tx.ExecContext(ctx,
+		o.placeholder.rebind(`INSERT INTO `+o.table+` (idempotence_key, reference, status, request, attempts, created_at, updated_at) VALUES (?, ?, ?, ?, 0, ?, ?)`),
+		key, reference, OutboxPending, string(request), now, now,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert outbox entry: %w", err)
+	}
+
+	return key, nil
+}
+
+// Get returns the entry by its idempotence key.
+func (o *Outbox) Get(ctx context.Context, idempotenceKey string) (OutboxEntry, error) {
+	row := o.db.QueryRowContext(ctx,
+		o.placeholder.rebind(`SELECT idempotence_key, reference, status, request, response, error, attempts, created_at, updated_at FROM `+o.table+` WHERE idempotence_key = ?`),
+		idempotenceKey,
+	)
+
+	entry, err := scanOutboxEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return OutboxEntry{}, ErrOutboxEntryNotFound
+	}
+
+	return entry, err
+}
+
+// Dispatch creates payments of up to limit pending entries, oldest first, and stores the results.
+// Pending entries with an expiring Idempotence-Key are moved to OutboxExpired without sending.
+// It returns the number of entries which were sent or failed.
+func (o *Outbox) Dispatch(ctx context.Context, limit int) (int, error) {
+	cutoff := time.Now().UTC().Add(-idempotenceKeyWindow)
+
+	_, err := o.db.ExecContext(ctx,
+		o.placeholder.rebind(`UPDATE `+o.table+` SET status = ?, error = ?, updated_at = ? WHERE status = ? AND created_at < ?`),
+		OutboxExpired, "idempotence key expired before the payment was confirmed", time.Now().UTC(), OutboxPending, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire outbox entries: %w", err)
+	}
+
+	rows, err := o.db.QueryContext(ctx,
+		o.placeholder.rebind(`SELECT idempotence_key, reference, status, request, response, error, attempts, created_at, updated_at FROM `+o.table+` WHERE status = ? AND created_at >= ? ORDER BY created_at LIMIT `+fmt.Sprint(limit)),
+		OutboxPending, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select outbox entries: %w", err)
+	}
+
+	var entries []OutboxEntry
+	for rows.Next() {
+		entry, err := scanOutboxEntry(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to select outbox entries: %w", err)
+	}
+
+	done := 0
+	for _, entry := range entries {
+		finished, err := o.dispatch(ctx, entry)
+		if err != nil {
+			return done, err
+		}
+		if finished {
+			done++
+		}
+	}
+
+	return done, nil
+}
+
+// Run dispatches pending entries every interval until the context is done.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration, limit int) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := o.Dispatch(ctx, limit); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch sends the entry and stores the result. Entries failed with network or 5xx errors stay pending.
+func (o *Outbox) dispatch(ctx context.Context, entry OutboxEntry) (bool, error) {
+	resp, sendErr := o.sender.SendRawPaymentRequest(WithIdempotenceKey(ctx, entry.IdempotenceKey), entry.Request)
+	if sendErr != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	status := OutboxSent
+	var response, errorText sql.NullString
+	switch apiErr, ok := AsAPIError(sendErr); {
+	case sendErr == nil:
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal payment response: %w", err)
+		}
+		response = sql.NullString{String: string(data), Valid: true}
+	case ok && isClientError(apiErr.StatusCode):
+		status = OutboxFailed
+		errorText = sql.NullString{String: sendErr.Error(), Valid: true}
+	default:
+		status = OutboxPending
+		errorText = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	_, err := o.db.ExecContext(ctx,
+		o.placeholder.rebind(`UPDATE `+o.table+` SET status = ?, response = ?, error = ?, attempts = attempts + 1, updated_at = ? WHERE idempotence_key = ?`),
+		status, response, errorText, time.Now().UTC(), entry.IdempotenceKey,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update outbox entry: %w", err)
+	}
+
+	return status != OutboxPending, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOutboxEntry(row rowScanner) (OutboxEntry, error) {
+	var (
+		entry             OutboxEntry
+		request           string
+		response, errText sql.NullString
+	)
+	err := row.Scan(&entry.IdempotenceKey, &entry.Reference, &entry.Status, &request, &response, &errText,
+		&entry.Attempts, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return OutboxEntry{}, err
+	}
+
+	entry.Request = json.RawMessage(request)
+	if response.Valid {
+		entry.Response = &PaymentResponse{}
+		if err = json.Unmarshal([]byte(response.String), entry.Response); err != nil {
+			return OutboxEntry{}, fmt.Errorf("failed to unmarshal payment response: %w", err)
+		}
+	}
+	entry.Error = errText.String
+
+	return entry, nil
+}
+
+// hasCardData reports whether the encoded payment request carries card data.
+func hasCardData(request []byte) bool {
+	var fields struct {
+		PaymentMethodData *struct {
+			Type string          `json:"type"`
+			Card json.RawMessage `json:"card"`
+		} `json:"payment_method_data"`
+	}
+	if err := json.Unmarshal(request, &fields); err != nil || fields.PaymentMethodData == nil {
+		return false
+	}
+
+	return fields.PaymentMethodData.Type == PaymentByBankCard || len(fields.PaymentMethodData.Card) > 0
+}
@@ -3,10 +3,13 @@ package yookassa
 import "time"
 
 const (
-	StatusPending  = "pending"
-	StatusWaiting  = "waiting_for_capture"
-	SatusSucceeded = "succeeded"
-	StatusCanceled = "canceled"
+	StatusPending   PaymentStatus = "pending"
+	StatusWaiting   PaymentStatus = "waiting_for_capture"
+	StatusSucceeded PaymentStatus = "succeeded"
+	StatusCanceled  PaymentStatus = "canceled"
+
+	// Deprecated: use StatusSucceeded.
+	SatusSucceeded = StatusSucceeded
 )
 
 type PaymentRequest struct {
@@ -31,7 +34,7 @@ type PaymentRequest struct {
 
 type PaymentResponse struct {
 	ID                   string                `json:"id"`
-	Status               string                `json:"status"`
+	Status               PaymentStatus         `json:"status"`
 	Test                 bool                  `json:"test"`
 	Paid                 bool                  `json:"paid"`
 	Refundable           bool                  `json:"refundable"`
@@ -44,7 +47,7 @@ type PaymentResponse struct {
 	Description          string                `json:"description"`
 	Recipient            Recipient             `json:"recipient"`
 	PaymentMethod        PaymentMethod         `json:"payment_method"`
-	ReceiptRegistration  string                `json:"receipt_registration,omitempty"`
+	ReceiptRegistration  ReceiptRegistrationStatus `json:"receipt_registration,omitempty"`
 	AuthorizationDetails *AuthorizationDetails `json:"authorization_details,omitempty"`
 	CancellationDetails  *CancellationDetails  `json:"cancellation_details,omitempty"`
 	Confirmation         ConfirmationInfo      `json:"confirmation"`
@@ -71,19 +74,28 @@ type Settlements struct {
 type Items struct {
 	Description string `json:"description"` // Name of the item (1-128 characters)
 	Amount      Amount `json:"amount"` // Price of the item
-	VatCode     int `json:"vat_code,omitempty"` // NDS code (1-6) see https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#vat-codes
+	VatCode     VatCode `json:"vat_code,omitempty"` // NDS code (1-12) see https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#vat-codes
 	Quantity    string `json:"quantity"` // Quantity of the item. Float or integer number
-	Measure     string `json:"measure,omitempty"` // Unit of measurement. As example "piece". Required parameter for Yookassa receipts
-	MarkQuantity MarkQuantity `json:"mark_quantity,omitempty"` // Mark quantity. As example "piece". Required parameter for Yookassa receipts
-	PaymentSubject string `json:"payment_subject,omitempty"` // Payment subject. As example "service or commodity" https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-subject
-	PaymentMode string `json:"payment_mode,omitempty"` // Payment mode. As example "full_payment" https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-mode
+	Measure     Measure `json:"measure,omitempty"` // Unit of measurement. As example "piece". Required parameter for Yookassa receipts
+	MarkQuantity *MarkQuantity `json:"mark_quantity,omitempty"` // Mark quantity. As example "piece". Required parameter for Yookassa receipts
+	PaymentSubject PaymentSubject `json:"payment_subject,omitempty"` // Payment subject. As example "service or commodity" https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-subject
+	PaymentMode PaymentMode `json:"payment_mode,omitempty"` // Payment mode. As example "full_payment" https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-mode
 	CountryOfOriginCode string `json:"country_of_origin_code,omitempty"` // Country of origin code. As example "RU" https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#country-of-origin
 	CustomsDeclarationNumber string `json:"customs_declaration_number,omitempty"` // Customs declaration number (1-32). As example "10714040/140917/0090376"
 	Excise string `json:"excise,omitempty"` // Sum of excise. As example "10.00"
 	ProductCode string `json:"product_code,omitempty"` // Unique number which appropriate for the product. Format: a number with 16th formation. Max length: 32bytes.  00 00 00 01 00 21 FA 41 00 23 05 41 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 12 00 AB 00
-	MarkCodeInfo MarkCodeInfo `json:"mark_code_info,omitempty"` // Mark code info. Must be filled one of fields
+	MarkCodeInfo *MarkCodeInfo `json:"mark_code_info,omitempty"` // Mark code info. Must be filled one of fields
 	MarkMode string `json:"mark_mode,omitempty"` // Mode of code processing. Must be filled "0"
-	PaymentSubjectIndustryDetails PaymentSubjectIndustryDetails `json:"payment_subject_industry_details,omitempty"` // Industry calculation details
+	PaymentSubjectIndustryDetails *PaymentSubjectIndustryDetails `json:"payment_subject_industry_details,omitempty"` // Industry calculation details
+	AgentType AgentType `json:"agent_type,omitempty"` // Type of the agent selling the item on behalf of the supplier. Requires supplier
+	Supplier *Supplier `json:"supplier,omitempty"` // Supplier of the item sold by the agent
+}
+
+// Supplier of the item sold by the agent. INN is required
+type Supplier struct {
+	Name  string `json:"name,omitempty"` // Name of the supplier
+	Phone string `json:"phone,omitempty"` // Phone of the supplier. As example "79000000000"
+	INN   string `json:"inn,omitempty"` // INN of the supplier (10 or 12 digits)
 }
 
 type PaymentSubjectIndustryDetails struct {
@@ -103,8 +115,8 @@ type MarkCodeInfo struct {
 	Gs1m string `json:"gs_1m,omitempty"` // GS1M code
 	Short string `json:"short,omitempty"` // Code of commodity in format - short code of mark
 	Fur string `json:"fur,omitempty"` // Control-identifier sign of fur commodity
-	Egails20 string `json:"egails_20,omitempty"` // Egails20 code
-	Egails30 string `json:"egails_30,omitempty"` // Egails30 code
+	Egails20 string `json:"egais_20,omitempty"` // EGAIS 2.0 code
+	Egails30 string `json:"egais_30,omitempty"` // EGAIS 3.0 code
 }
 
 type MarkQuantity struct {
@@ -152,9 +164,9 @@ type ReceiptRequestData struct {
 	Items    []Items      `json:"items,omitempty"`    // List of items. No more than 100 items.
 	Phone    string       `json:"phone,omitempty"`    // Phone of the customer for send a receipt. It is obsolete - recommend input to receipt.customer.phone
 	Email    string       `json:"email,omitempty"`    // Email of the customer for send a receipt. It is obsolete - recommend input to receipt.customer.email
-	TaxSystemCode string `json:"tax_system_code,omitempty"` // Tax system code (1-6). For Yookassa it is not required
-	ReceiptIndustryDetails ReceiptIndustryDetails `json:"receipt_industry_details,omitempty"` // Industry calculation details
-	ReceiptOperatorDetails ReceiptOperatorDetails `json:"receipt_operator_details,omitempty"` // Operator calculation details
+	TaxSystemCode TaxSystem `json:"tax_system_code,omitempty"` // Tax system code (1-6). For Yookassa it is not required
+	ReceiptIndustryDetails *ReceiptIndustryDetails `json:"receipt_industry_details,omitempty"` // Industry calculation details
+	ReceiptOperatorDetails *ReceiptOperatorDetails `json:"receipt_operator_details,omitempty"` // Operator calculation details
 }
 
 type ReceiptOperatorDetails struct {
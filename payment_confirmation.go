@@ -53,6 +53,10 @@ type ConfirmationInfo struct {
 }
 
 func (c *ConfirmationInfo) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
 	c.Type = jsoniter.Get(data, "type").ToString()
 
 	switch c.Type {
@@ -74,6 +78,19 @@ func (c *ConfirmationInfo) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON returns the details of the confirmation as the API sends them, so
+// the encoded value can be decoded back by UnmarshalJSON.
+func (c ConfirmationInfo) MarshalJSON() ([]byte, error) {
+	if c.Type == "" {
+		return []byte("null"), nil
+	}
+	if c.Details == nil {
+		return jsoniter.Marshal(&Confirmation{Type: c.Type})
+	}
+
+	return jsoniter.Marshal(c.Details)
+}
+
 type RedirectConfirmationDetails struct {
 	Type            string `json:"type"`
 	Enforce         bool   `json:"enforce"`
@@ -123,6 +123,31 @@ func (m *PaymentMethod) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON returns the payment method with the fields of the details at the top level,
+// as the API expects and sends them, so the encoded value can be decoded back by UnmarshalJSON.
+func (m PaymentMethod) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{}
+	if m.Details != nil {
+		data, err := jsoniter.Marshal(m.Details)
+		if err != nil {
+			return nil, err
+		}
+		if err = jsoniter.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	fields["type"] = m.Type
+	if m.ID != "" {
+		fields["id"] = m.ID
+	}
+	if m.Saved {
+		fields["saved"] = m.Saved
+	}
+
+	return jsoniter.Marshal(fields)
+}
+
 type BankCardPaymentDetails struct {
 	Card BankCardInfo `json:"card"`
 }
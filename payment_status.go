@@ -0,0 +1,113 @@
+package yookassa
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIllegalTransition is returned when the payment can not move from one status to another,
+	// as example when stale data tries to move a succeeded payment back to pending.
+	ErrIllegalTransition = errors.New("illegal payment status transition")
+	// ErrOperationNotAllowed is returned when the operation is not allowed in the payment status.
+	ErrOperationNotAllowed = errors.New("operation is not allowed in payment status")
+)
+
+// PaymentStatus is the status of the payment.
+// See https://yookassa.ru/developers/payment-acceptance/getting-started/payment-process#lifecycle
+type PaymentStatus string
+
+// PaymentOperation is an operation which changes the payment.
+type PaymentOperation string
+
+const (
+	PaymentOperationCapture PaymentOperation = "capture"
+	PaymentOperationCancel  PaymentOperation = "cancel"
+	PaymentOperationRefund  PaymentOperation = "refund"
+)
+
+// paymentTransitions lists statuses the payment may move to from the status.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	StatusPending:   {StatusWaiting, StatusSucceeded, StatusCanceled},
+	StatusWaiting:   {StatusSucceeded, StatusCanceled},
+	StatusSucceeded: nil,
+	StatusCanceled:  nil,
+}
+
+// paymentOperations lists operations allowed in the status.
+var paymentOperations = map[PaymentStatus][]PaymentOperation{
+	StatusWaiting:   {PaymentOperationCapture, PaymentOperationCancel},
+	StatusSucceeded: {PaymentOperationRefund},
+}
+
+// IsValid reports whether the status is known.
+func (s PaymentStatus) IsValid() bool {
+	_, ok := paymentTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the status can not change anymore.
+func (s PaymentStatus) IsTerminal() bool {
+	return s == StatusSucceeded || s == StatusCanceled
+}
+
+// Precedence orders statuses along the lifecycle: pending, waiting_for_capture, then terminal ones.
+// Unknown statuses have zero precedence.
+func (s PaymentStatus) Precedence() int {
+	switch s {
+	case StatusPending:
+		return 1
+	case StatusWaiting:
+		return 2
+	case StatusSucceeded, StatusCanceled:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// CanTransitionTo reports whether the payment may move from s to next.
+// Staying in the same status is always allowed.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	if s == next {
+		return true
+	}
+
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows reports whether the operation is allowed in the status.
+func (s PaymentStatus) Allows(operation PaymentOperation) bool {
+	for _, allowed := range paymentOperations[s] {
+		if allowed == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateTransition returns ErrIllegalTransition if the payment can not move from one status to another.
+func ValidateTransition(from, to PaymentStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: from %q to %q", ErrIllegalTransition, from, to)
+	}
+
+	return nil
+}
+
+// CheckOperation returns ErrOperationNotAllowed if the operation can not be applied to the payment.
+// Refunds also require the payment to be refundable.
+func (p PaymentResponse) CheckOperation(operation PaymentOperation) error {
+	if !p.Status.Allows(operation) || (operation == PaymentOperationRefund && !p.Refundable) {
+		return fmt.Errorf("%w: %s in %q", ErrOperationNotAllowed, operation, p.Status)
+	}
+
+	return nil
+}
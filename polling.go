@@ -0,0 +1,127 @@
+package yookassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrUnexpectedStatus is returned by WaitForStatus when the payment reached a terminal
+// status other than the awaited ones.
+var ErrUnexpectedStatus = errors.New("payment reached unexpected status")
+
+// PollBackoff configures the polling of WaitForStatus and Watch.
+// The interval starts from Initial and is multiplied by Multiplier after every poll up to Max.
+type PollBackoff struct {
+	Initial    time.Duration // Default 1 second
+	Max        time.Duration // Default 10 seconds
+	Multiplier float64       // Default 1.5
+}
+
+var defaultPollBackoff = PollBackoff{
+	Initial:    time.Second,
+	Max:        10 * time.Second,
+	Multiplier: 1.5,
+}
+
+func (b PollBackoff) next(interval time.Duration) time.Duration {
+	interval = time.Duration(float64(interval) * b.Multiplier)
+	if interval > b.Max {
+		return b.Max
+	}
+
+	return interval
+}
+
+// PaymentStatusChange is sent by Watch when the status of the payment changes or polling fails.
+type PaymentStatusChange struct {
+	Payment PaymentResponse // The payment with the new status
+	Err     error           // Error of the polling. The channel is closed after it
+}
+
+// Watch polls the payment and sends its status changes to the returned channel, starting with
+// the current status. The channel is closed when the payment reaches a terminal status,
+// the context is done or the API answers with a 4xx error. Other errors are retried.
+func (c *yooKassaClient) Watch(ctx context.Context, paymentID string) <-chan PaymentStatusChange {
+	changes := make(chan PaymentStatusChange)
+
+	go func() {
+		defer close(changes)
+
+		send := func(change PaymentStatusChange) bool {
+			select {
+			case changes <- change:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var lastStatus PaymentStatus
+		interval := c.pollBackoff.Initial
+		for {
+			payment, err := c.GetPayment(ctx, paymentID)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if apiErr, ok := AsAPIError(err); ok && isClientError(apiErr.StatusCode) {
+					send(PaymentStatusChange{Err: err})
+					return
+				}
+			case payment.Status != lastStatus:
+				lastStatus = payment.Status
+				if !send(PaymentStatusChange{Payment: payment}) || payment.Status.IsTerminal() {
+					return
+				}
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			interval = c.pollBackoff.next(interval)
+		}
+	}()
+
+	return changes
+}
+
+// WaitForStatus polls the payment until it has one of the statuses and returns it.
+// Without statuses it waits for a terminal status. If the payment reaches a terminal status
+// which is not awaited, the payment is returned with ErrUnexpectedStatus.
+func (c *yooKassaClient) WaitForStatus(ctx context.Context, paymentID string, statuses ...PaymentStatus) (PaymentResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for change := range c.Watch(ctx, paymentID) {
+		if change.Err != nil {
+			return PaymentResponse{}, change.Err
+		}
+
+		status := change.Payment.Status
+		if len(statuses) == 0 && status.IsTerminal() {
+			return change.Payment, nil
+		}
+		for _, s := range statuses {
+			if s == status {
+				return change.Payment, nil
+			}
+		}
+		if status.IsTerminal() {
+			return change.Payment, fmt.Errorf("%w: %s", ErrUnexpectedStatus, status)
+		}
+	}
+
+	return PaymentResponse{}, ctx.Err()
+}
+
+func isClientError(statusCode int) bool {
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError &&
+		statusCode != http.StatusTooManyRequests
+}
@@ -0,0 +1,145 @@
+package yookassa
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidProductCode is returned for values which can not be encoded or decoded as tag 1162.
+var ErrInvalidProductCode = errors.New("invalid product code")
+
+// maxProductCodeLength is the maximum length of tag 1162 in bytes.
+const maxProductCodeLength = 32
+
+// ProductCodeType is the type of the marking in the first two bytes of tag 1162.
+type ProductCodeType uint16
+
+const (
+	ProductCodeUnknown    ProductCodeType = 0x0000 // The code can not be determined
+	ProductCodeEan8       ProductCodeType = 0x4508 // EAN-8
+	ProductCodeEan13      ProductCodeType = 0x450D // EAN-13
+	ProductCodeItf14      ProductCodeType = 0x490E // ITF-14
+	ProductCodeDataMatrix ProductCodeType = 0x444D // GS1 DataMatrix of Chestny ZNAK
+	ProductCodeFur        ProductCodeType = 0x5246 // Control and identification sign of fur
+	ProductCodeEgais20    ProductCodeType = 0xC514 // EGAIS 2.0
+	ProductCodeEgais30    ProductCodeType = 0xC51E // EGAIS 3.0
+)
+
+// ProductCode is the decoded nomenclature code (tag 1162).
+type ProductCode struct {
+	Type   ProductCodeType
+	GTIN   string // GTIN padded to 14 digits. Empty for fur and EGAIS codes
+	Serial string // Serial number, the sign of fur or the EGAIS code
+}
+
+// hasGTIN reports whether the code type holds 6 bytes of GTIN after the type.
+func (t ProductCodeType) hasGTIN() bool {
+	switch t {
+	case ProductCodeEan8, ProductCodeEan13, ProductCodeItf14, ProductCodeDataMatrix:
+		return true
+	default:
+		return false
+	}
+}
+
+// EncodeProductCode builds the value of Items.ProductCode: two bytes of the type, six bytes of GTIN
+// as a big-endian number and the serial number in ASCII, formatted as space separated hex bytes.
+// As example GTIN 04600439931256 with serial "JgXJ5.T" of ProductCodeDataMatrix is
+// "44 4D 04 2F 1F 96 81 78 4A 67 58 4A 35 2E 54".
+// For fur and EGAIS codes the GTIN is ignored and the serial holds the code or, for EGAIS,
+// its part written to the tag.
+func EncodeProductCode(codeType ProductCodeType, gtin, serial string) (string, error) {
+	data := []byte{byte(codeType >> 8), byte(codeType)}
+
+	if codeType.hasGTIN() {
+		n, err := strconv.ParseUint(gtin, 10, 64)
+		if err != nil || len(gtin) > 14 || n >= 1<<48 {
+			return "", fmt.Errorf("%w: GTIN %q must be at most 14 digits", ErrInvalidProductCode, gtin)
+		}
+		data = append(data, byte(n>>40), byte(n>>32), byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+
+	for _, r := range serial {
+		if r < 0x20 || r > 0x7e {
+			return "", fmt.Errorf("%w: serial %q must be printable ASCII", ErrInvalidProductCode, serial)
+		}
+	}
+	data = append(data, serial...)
+
+	if len(data) > maxProductCodeLength {
+		return "", fmt.Errorf("%w: %d bytes, at most %d are allowed", ErrInvalidProductCode, len(data), maxProductCodeLength)
+	}
+
+	return formatHexBytes(data), nil
+}
+
+// DecodeProductCode parses the value of Items.ProductCode. Spaces between the bytes are optional.
+func DecodeProductCode(value string) (ProductCode, error) {
+	data, err := hex.DecodeString(strings.ReplaceAll(value, " ", ""))
+	if err != nil {
+		return ProductCode{}, fmt.Errorf("%w: %v", ErrInvalidProductCode, err)
+	}
+	if len(data) < 2 || len(data) > maxProductCodeLength {
+		return ProductCode{}, fmt.Errorf("%w: length %d", ErrInvalidProductCode, len(data))
+	}
+
+	code := ProductCode{Type: ProductCodeType(data[0])<<8 | ProductCodeType(data[1])}
+	data = data[2:]
+
+	if code.Type.hasGTIN() {
+		if len(data) < 6 {
+			return ProductCode{}, fmt.Errorf("%w: GTIN is truncated", ErrInvalidProductCode)
+		}
+		var n uint64
+		for _, b := range data[:6] {
+			n = n<<8 | uint64(b)
+		}
+		code.GTIN = fmt.Sprintf("%014d", n)
+		data = data[6:]
+	}
+	code.Serial = string(data)
+
+	return code, nil
+}
+
+// Parts of the EGAIS codes written to tag 1162: characters 9-31 of the PDF417 code of EGAIS 2.0
+// and the first 14 characters of the DataMatrix code of EGAIS 3.0.
+const (
+	egais20Start  = 8
+	egais20End    = 31
+	egais30Length = 14
+)
+
+// ProductCode returns the value of Items.ProductCode for the parsed mark code.
+func (m MarkCode) ProductCode() (string, error) {
+	switch m.Format {
+	case MarkCodeFormatEan8:
+		return EncodeProductCode(ProductCodeEan8, m.GTIN, "")
+	case MarkCodeFormatEan13:
+		return EncodeProductCode(ProductCodeEan13, m.GTIN, "")
+	case MarkCodeFormatItf14:
+		return EncodeProductCode(ProductCodeItf14, m.GTIN, "")
+	case MarkCodeFormatGs10, MarkCodeFormatGs1m, MarkCodeFormatShort:
+		return EncodeProductCode(ProductCodeDataMatrix, m.GTIN, m.Serial)
+	case MarkCodeFormatFur:
+		return EncodeProductCode(ProductCodeFur, "", m.Raw)
+	case MarkCodeFormatEgais20:
+		return EncodeProductCode(ProductCodeEgais20, "", m.Raw[egais20Start:egais20End])
+	case MarkCodeFormatEgais30:
+		return EncodeProductCode(ProductCodeEgais30, "", m.Raw[:egais30Length])
+	default:
+		return "", fmt.Errorf("%w: format %s is not supported", ErrInvalidProductCode, m.Format)
+	}
+}
+
+func formatHexBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, " ")
+}
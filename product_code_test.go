@@ -0,0 +1,155 @@
+package yookassa
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Chestny ZNAK code from the example of tag 1162 in the marking guidelines,
+// with the crypto tail and GS separators as scanned.
+const testDataMatrixCode = "010460043993125621JgXJ5.T\x1d8005112000\x1d930001\x1d923zbrLA=="
+
+func TestEncodeProductCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		codeType ProductCodeType
+		gtin     string
+		serial   string
+		want     string
+	}{
+		{
+			name:     "data matrix",
+			codeType: ProductCodeDataMatrix,
+			gtin:     "04600439931256",
+			serial:   "JgXJ5.T",
+			want:     "44 4D 04 2F 1F 96 81 78 4A 67 58 4A 35 2E 54",
+		},
+		{
+			name:     "ean 13",
+			codeType: ProductCodeEan13,
+			gtin:     "4606203090785",
+			want:     "45 0D 04 30 77 19 57 61",
+		},
+		{
+			name:     "ean 8",
+			codeType: ProductCodeEan8,
+			gtin:     "96385074",
+			want:     "45 08 00 00 05 BE B8 32",
+		},
+		{
+			name:     "fur",
+			codeType: ProductCodeFur,
+			serial:   "RU-401301-AAA0277031",
+			want:     "52 46 52 55 2D 34 30 31 33 30 31 2D 41 41 41 30 32 37 37 30 33 31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeProductCode(tt.codeType, tt.gtin, tt.serial)
+			if err != nil {
+				t.Fatalf("EncodeProductCode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			decoded, err := DecodeProductCode(got)
+			if err != nil {
+				t.Fatalf("DecodeProductCode: %v", err)
+			}
+			if decoded.Type != tt.codeType || decoded.Serial != tt.serial {
+				t.Errorf("decoded %+v", decoded)
+			}
+			if tt.codeType.hasGTIN() && strings.TrimLeft(decoded.GTIN, "0") != strings.TrimLeft(tt.gtin, "0") {
+				t.Errorf("decoded GTIN %q, want %q", decoded.GTIN, tt.gtin)
+			}
+		})
+	}
+}
+
+func TestEncodeProductCodeRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		codeType ProductCodeType
+		gtin     string
+		serial   string
+	}{
+		{"long gtin", ProductCodeEan13, "123456789012345", ""},
+		{"non-digit gtin", ProductCodeEan13, "46062030907X5", ""},
+		{"non-ascii serial", ProductCodeDataMatrix, "04600439931256", "серия"},
+		{"too long", ProductCodeFur, "", strings.Repeat("A", 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EncodeProductCode(tt.codeType, tt.gtin, tt.serial); !errors.Is(err, ErrInvalidProductCode) {
+				t.Errorf("got error %v, want ErrInvalidProductCode", err)
+			}
+		})
+	}
+}
+
+func TestDecodeProductCodeWithoutSpaces(t *testing.T) {
+	code, err := DecodeProductCode("444D042F1F9681784A67584A352E54")
+	if err != nil {
+		t.Fatalf("DecodeProductCode: %v", err)
+	}
+	if code.Type != ProductCodeDataMatrix || code.GTIN != "04600439931256" || code.Serial != "JgXJ5.T" {
+		t.Errorf("got %+v", code)
+	}
+}
+
+func TestMarkCodeProductCode(t *testing.T) {
+	egais20 := "22N00001D3KJLP8M2RS4T1A7" + strings.Repeat("0", 44)
+	egais30 := "13622498123456" + strings.Repeat("A", 136)
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "data matrix",
+			raw:  testDataMatrixCode,
+			want: "44 4D 04 2F 1F 96 81 78 4A 67 58 4A 35 2E 54",
+		},
+		{
+			name: "ean 13",
+			raw:  "4606203090785",
+			want: "45 0D 04 30 77 19 57 61",
+		},
+		{
+			name: "egais 2.0",
+			raw:  egais20,
+			want: "C5 14 " + asciiHex(egais20[8:31]),
+		},
+		{
+			name: "egais 3.0",
+			raw:  egais30,
+			want: "C5 1E " + asciiHex(egais30[:14]),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mark, err := ParseMarkCode(tt.raw)
+			if err != nil {
+				t.Fatalf("ParseMarkCode: %v", err)
+			}
+
+			got, err := mark.ProductCode()
+			if err != nil {
+				t.Fatalf("ProductCode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func asciiHex(s string) string {
+	return formatHexBytes([]byte(s))
+}
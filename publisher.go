@@ -0,0 +1,164 @@
+package yookassa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// PublishedEventSchemaVersion is the version of PublishedEvent. It changes only when
+// the fields of PublishedEvent change, independently of the YooKassa payload.
+const PublishedEventSchemaVersion = 1
+
+// PublishedEvent is the stable representation of a notification written to a Publisher.
+type PublishedEvent struct {
+	SchemaVersion int             `json:"schema_version"` // Version of the schema. See PublishedEventSchemaVersion
+	ID            string          `json:"id"`             // Object ID and event. Equal for redeliveries, use it to deduplicate
+	Event         string          `json:"event"`          // Event of the notification. As example "payment.succeeded"
+	ObjectID      string          `json:"object_id"`      // ID of the payment or refund
+	ObjectStatus  string          `json:"object_status"`  // Status of the payment or refund
+	ReceivedAt    time.Time       `json:"received_at"`    // Time the notification was received
+	Payload       json.RawMessage `json:"payload"`        // Object of the notification as sent by YooKassa
+}
+
+// NewPublishedEvent converts the notification to the event.
+func NewPublishedEvent(notification Notification) (PublishedEvent, error) {
+	var object struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(notification.Object, &object); err != nil {
+		return PublishedEvent{}, fmt.Errorf("failed to unmarshal notification object: %w", err)
+	}
+
+	return PublishedEvent{
+		SchemaVersion: PublishedEventSchemaVersion,
+		ID:            object.ID + ":" + notification.Event,
+		Event:         notification.Event,
+		ObjectID:      object.ID,
+		ObjectStatus:  object.Status,
+		ReceivedAt:    time.Now().UTC(),
+		Payload:       notification.Object,
+	}, nil
+}
+
+// Publisher writes events to a message bus, a file or anything else.
+type Publisher interface {
+	Publish(ctx context.Context, event PublishedEvent) error
+}
+
+// PublishNotifications returns a NotificationHandlerFunc which publishes notifications.
+//
+// Delivery is at least once: if Publish fails the webhook answers 500 and YooKassa
+// delivers the notification again, and a notification may be published more than once
+// when YooKassa repeats it. Consumers deduplicate by PublishedEvent.ID.
+func PublishNotifications(publisher Publisher) NotificationHandlerFunc {
+	return func(ctx context.Context, notification Notification) error {
+		event, err := NewPublishedEvent(notification)
+		if err != nil {
+			return err
+		}
+
+		if err = publisher.Publish(ctx, event); err != nil {
+			return fmt.Errorf("failed to publish event: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// ChannelPublisher sends events to a channel, as example for in-process consumers.
+type ChannelPublisher struct {
+	events chan PublishedEvent
+}
+
+// NewChannelPublisher creates a publisher with a channel of the given capacity.
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{events: make(chan PublishedEvent, size)}
+}
+
+// Events returns the channel of published events.
+func (p *ChannelPublisher) Events() <-chan PublishedEvent {
+	return p.events
+}
+
+// Publish waits until the event is accepted by the channel or the context is done.
+func (p *ChannelPublisher) Publish(ctx context.Context, event PublishedEvent) error {
+	select {
+	case p.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriterPublisher writes events to the writer as JSON lines.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterPublisher creates a publisher to the writer.
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// Publish writes the event as one line of JSON.
+func (p *WriterPublisher) Publish(_ context.Context, event PublishedEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(line)
+
+	return err
+}
+
+// FilePublisher appends events to a JSONL file and syncs it after every event,
+// so a published event survives a crash.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens the file for appending, creating it if needed.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+
+	return &FilePublisher{file: file}, nil
+}
+
+// Publish appends the event as one line of JSON.
+func (p *FilePublisher) Publish(_ context.Context, event PublishedEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err = p.file.Write(line); err != nil {
+		return err
+	}
+
+	return p.file.Sync()
+}
+
+// Close closes the file.
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
@@ -0,0 +1,107 @@
+package yookassa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testNotification = `{"type":"notification","event":"payment.succeeded","object":{"id":"22d6d597-000f-5000-9000-145f6df21d6f","status":"succeeded","paid":true}}`
+
+// flakyPublisher fails the first failures calls and records the published events.
+type flakyPublisher struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	events   []PublishedEvent
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, event PublishedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("bus is unavailable")
+	}
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func deliverNotification(t *testing.T, handler http.Handler, body string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func TestPublishNotificationsRedeliversOnPublishFailure(t *testing.T) {
+	publisher := &flakyPublisher{failures: 1}
+	handler := NewWebhookHandler(PublishNotifications(publisher))
+
+	if code := deliverNotification(t, handler, testNotification); code != http.StatusInternalServerError {
+		t.Fatalf("first delivery: got status %d, want %d so YooKassa redelivers", code, http.StatusInternalServerError)
+	}
+	if len(publisher.events) != 0 {
+		t.Fatalf("first delivery: got %d published events, want 0", len(publisher.events))
+	}
+
+	if code := deliverNotification(t, handler, testNotification); code != http.StatusOK {
+		t.Fatalf("redelivery: got status %d, want %d", code, http.StatusOK)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("redelivery: got %d published events, want 1", len(publisher.events))
+	}
+
+	event := publisher.events[0]
+	if event.ID != "22d6d597-000f-5000-9000-145f6df21d6f:payment.succeeded" {
+		t.Errorf("got event ID %q", event.ID)
+	}
+	if event.ObjectStatus != "succeeded" || event.SchemaVersion != PublishedEventSchemaVersion {
+		t.Errorf("got event %+v", event)
+	}
+}
+
+func TestPublishNotificationsPublishesRepeatedNotificationAgain(t *testing.T) {
+	publisher := &flakyPublisher{}
+	handler := NewWebhookHandler(PublishNotifications(publisher))
+
+	for i := 0; i < 2; i++ {
+		if code := deliverNotification(t, handler, testNotification); code != http.StatusOK {
+			t.Fatalf("delivery %d: got status %d, want %d", i+1, code, http.StatusOK)
+		}
+	}
+
+	if len(publisher.events) != 2 {
+		t.Fatalf("got %d published events, want 2 for a repeated notification", len(publisher.events))
+	}
+	if publisher.events[0].ID != publisher.events[1].ID {
+		t.Errorf("duplicates have different IDs %q and %q, consumers can not deduplicate them",
+			publisher.events[0].ID, publisher.events[1].ID)
+	}
+}
+
+func TestChannelPublisherReturnsErrorWhenContextIsDone(t *testing.T) {
+	publisher := NewChannelPublisher(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	handle := PublishNotifications(publisher)
+	notification := Notification{
+		Type:   "notification",
+		Event:  EventPaymentSucceeded,
+		Object: []byte(`{"id":"22d6d597-000f-5000-9000-145f6df21d6f","status":"succeeded"}`),
+	}
+	if err := handle(ctx, notification); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled so the notification is redelivered", err)
+	}
+}
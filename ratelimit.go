@@ -0,0 +1,139 @@
+package yookassa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Classes of the operations limited separately by WithRateLimit.
+const (
+	OperationClassCreate = "create" // Operations which create or change objects (POST)
+	OperationClassRead   = "read"   // Operations which read objects (GET)
+)
+
+// RateLimit configures the client-side limiting of requests.
+type RateLimit struct {
+	RequestsPerSecond float64 // Rate of the token bucket. Zero disables the rate limiting
+	Burst             int     // Capacity of the token bucket. At least 1
+	MaxInFlight       int     // Maximum number of concurrent requests. Zero disables the limit
+}
+
+// rateLimiter combines a token bucket with a semaphore of requests in flight.
+// It is paused when the API answers 429, so other requests do not hit the limit too.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+
+	inFlight chan struct{}
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &rateLimiter{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	return l
+}
+
+// wait blocks until the request is allowed. The caller must call release after the request
+// if wait returned no error. It fails at once if the wait would exceed the ctx deadline.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			l.release()
+			return fmt.Errorf("rate limit wait of %s exceeds deadline: %w", delay, context.DeadlineExceeded)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.release()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *rateLimiter) release() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// reserve takes a token and returns zero or returns the time to wait for the next one.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause stops issuing requests for d, as example after 429 with retry_after.
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// operationClass returns the class of the request for the rate limiting.
+func operationClass(method string) string {
+	if method == http.MethodGet {
+		return OperationClassRead
+	}
+
+	return OperationClassCreate
+}
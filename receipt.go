@@ -0,0 +1,110 @@
+package yookassa
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Types of the receipts.
+const (
+	ReceiptTypePayment = "payment"
+	ReceiptTypeRefund  = "refund"
+)
+
+// Types of the receipt settlements.
+const (
+	SettlementCashless      = "cashless"
+	SettlementPrepayment    = "prepayment"
+	SettlementPostpayment   = "postpayment"
+	SettlementConsideration = "consideration"
+)
+
+// ReceiptRequest creates a separate receipt, as example the second receipt of the final settlement.
+// See https://yookassa.ru/developers/api#create_receipt
+type ReceiptRequest struct {
+	Type          string        `json:"type"`                      // ReceiptTypePayment or ReceiptTypeRefund
+	PaymentID     string        `json:"payment_id,omitempty"`      // ID of the payment the receipt belongs to
+	RefundID      string        `json:"refund_id,omitempty"`       // ID of the refund the receipt belongs to
+	Customer      Customer      `json:"customer"`                  // Information about the customer. At least an email or phone
+	Items         []Items       `json:"items"`                     // List of items. No more than 100 items
+	Send          bool          `json:"send"`                      // Send the receipt to the customer. Always true
+	TaxSystemCode TaxSystem     `json:"tax_system_code,omitempty"` // Tax system code (1-6)
+	Settlements   []Settlements `json:"settlements"`               // Settlements of the receipt
+}
+
+// ReceiptResponse is the receipt registered by YooKassa.
+type ReceiptResponse struct {
+	ID                   string                    `json:"id"`
+	Type                 string                    `json:"type"`
+	PaymentID            string                    `json:"payment_id,omitempty"`
+	RefundID             string                    `json:"refund_id,omitempty"`
+	Status               ReceiptRegistrationStatus `json:"status"`
+	FiscalDocumentNumber string                    `json:"fiscal_document_number,omitempty"`
+	FiscalStorageNumber  string                    `json:"fiscal_storage_number,omitempty"`
+	FiscalAttribute      string                    `json:"fiscal_attribute,omitempty"`
+	RegisteredAt         *time.Time                `json:"registered_at,omitempty"`
+	FiscalProviderID     string                    `json:"fiscal_provider_id,omitempty"`
+	Items                []Items                   `json:"items"`
+	Settlements          []Settlements             `json:"settlements,omitempty"`
+	TaxSystemCode        TaxSystem                 `json:"tax_system_code,omitempty"`
+}
+
+// ReceiptRegistrationStatus is the status of the receipt registration in the tax service.
+type ReceiptRegistrationStatus string
+
+const (
+	ReceiptRegistrationPending   ReceiptRegistrationStatus = "pending"
+	ReceiptRegistrationSucceeded ReceiptRegistrationStatus = "succeeded"
+	ReceiptRegistrationCanceled  ReceiptRegistrationStatus = "canceled"
+)
+
+// IsFinal reports whether the registration is finished.
+func (s ReceiptRegistrationStatus) IsFinal() bool {
+	return s == ReceiptRegistrationSucceeded || s == ReceiptRegistrationCanceled
+}
+
+type receiptList struct {
+	Items      []ReceiptResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// SendReceiptRequest creates the receipt.
+func (c *yooKassaClient) SendReceiptRequest(ctx context.Context, receiptRequest ReceiptRequest) (ReceiptResponse, error) {
+	var receiptResponse ReceiptResponse
+	if err := c.do(ctx, OperationCreateReceipt, http.MethodPost, c.baseURL+"/receipts", receiptRequest, &receiptResponse); err != nil {
+		return ReceiptResponse{}, err
+	}
+
+	return receiptResponse, nil
+}
+
+// GetReceipt retrieves the receipt with the given ID.
+func (c *yooKassaClient) GetReceipt(ctx context.Context, receiptID string) (ReceiptResponse, error) {
+	var receiptResponse ReceiptResponse
+	if err := c.do(ctx, OperationGetReceipt, http.MethodGet, c.baseURL+"/receipts/"+receiptID, nil, &receiptResponse); err != nil {
+		return ReceiptResponse{}, err
+	}
+
+	return receiptResponse, nil
+}
+
+// ListReceipts retrieves all receipts of the payment.
+func (c *yooKassaClient) ListReceipts(ctx context.Context, paymentID string) ([]ReceiptResponse, error) {
+	var receipts []ReceiptResponse
+
+	query := url.Values{"payment_id": {paymentID}}
+	for {
+		var list receiptList
+		if err := c.do(ctx, OperationListReceipts, http.MethodGet, c.baseURL+"/receipts?"+query.Encode(), nil, &list); err != nil {
+			return nil, err
+		}
+
+		receipts = append(receipts, list.Items...)
+		if list.NextCursor == "" {
+			return receipts, nil
+		}
+		query.Set("cursor", list.NextCursor)
+	}
+}
@@ -0,0 +1,199 @@
+package yookassa
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxReceiptItems is the maximum number of items in one receipt.
+const MaxReceiptItems = 100
+
+// ItemOption sets optional fields of the receipt item.
+type ItemOption func(*Items)
+
+// WithPaymentSubject sets the payment subject of the item. As example "commodity".
+func WithPaymentSubject(subject PaymentSubject) ItemOption {
+	return func(i *Items) {
+		i.PaymentSubject = subject
+	}
+}
+
+// WithPaymentMode sets the payment mode of the item. As example "full_payment".
+func WithPaymentMode(mode PaymentMode) ItemOption {
+	return func(i *Items) {
+		i.PaymentMode = mode
+	}
+}
+
+// WithMeasure sets the unit of measurement of the item. As example "piece".
+func WithMeasure(measure Measure) ItemOption {
+	return func(i *Items) {
+		i.Measure = measure
+	}
+}
+
+// WithMarking sets the mark code of the item and the mark mode "0".
+func WithMarking(code MarkCodeInfo) ItemOption {
+	return func(i *Items) {
+		i.MarkCodeInfo = &code
+		i.MarkMode = "0"
+	}
+}
+
+// WithMarkQuantity sets the fractional quantity of the marked item.
+func WithMarkQuantity(numerator, denominator int) ItemOption {
+	return func(i *Items) {
+		i.MarkQuantity = &MarkQuantity{
+			Numerator:   fmt.Sprint(numerator),
+			Denominator: fmt.Sprint(denominator),
+		}
+	}
+}
+
+// WithCountryOfOrigin sets the country of origin and the customs declaration number of the item.
+func WithCountryOfOrigin(countryCode, customsDeclarationNumber string) ItemOption {
+	return func(i *Items) {
+		i.CountryOfOriginCode = countryCode
+		i.CustomsDeclarationNumber = customsDeclarationNumber
+	}
+}
+
+// WithProductCode sets the nomenclature code of the item (tag 1162).
+func WithProductCode(code string) ItemOption {
+	return func(i *Items) {
+		i.ProductCode = code
+	}
+}
+
+// ReceiptBuilder assembles ReceiptRequestData item by item.
+// Errors are collected and returned by Build.
+//
+//	receipt, err := yookassa.NewReceiptBuilder("RUB").
+//		Customer(yookassa.Customer{Email: "user@example.com"}).
+//		AddItem("Coffee", "150.00", "2", yookassa.VatNone, yookassa.WithPaymentSubject(yookassa.PaymentSubjectCommodity)).
+//		Build()
+type ReceiptBuilder struct {
+	currency string
+	receipt  ReceiptRequestData
+	total    int64
+	errs     []error
+}
+
+// NewReceiptBuilder creates a builder of the receipt in the currency.
+func NewReceiptBuilder(currency string) *ReceiptBuilder {
+	return &ReceiptBuilder{currency: currency}
+}
+
+// Customer sets the customer who receives the receipt.
+func (b *ReceiptBuilder) Customer(customer Customer) *ReceiptBuilder {
+	b.receipt.Customer = customer
+	return b
+}
+
+// TaxSystem sets the tax system code of the shop.
+func (b *ReceiptBuilder) TaxSystem(code TaxSystem) *ReceiptBuilder {
+	b.receipt.TaxSystemCode = code
+	return b
+}
+
+// AddItem adds the item with the price of one unit like "10.00", the quantity like "1.5" and the VAT code.
+func (b *ReceiptBuilder) AddItem(description, unitPrice, quantity string, vatCode VatCode, opts ...ItemOption) *ReceiptBuilder {
+	n := len(b.receipt.Items) + 1
+
+	price, err := ParseMinorUnits(unitPrice)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("item %d: price: %w", n, err))
+		return b
+	}
+	qty, err := ParseQuantity(quantity)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("item %d: quantity: %w", n, err))
+		return b
+	}
+
+	item := Items{
+		Description: description,
+		Amount:      NewAmount(price, b.currency),
+		VatCode:     vatCode,
+		Quantity:    FormatQuantity(qty),
+	}
+	for _, o := range opts {
+		o(&item)
+	}
+
+	b.receipt.Items = append(b.receipt.Items, item)
+	b.total += MultiplyQuantity(price, qty)
+
+	return b
+}
+
+// Total returns the sum of all items. It must be equal to the amount of the payment.
+func (b *ReceiptBuilder) Total() Amount {
+	return NewAmount(b.total, b.currency)
+}
+
+// Build validates and returns the receipt.
+func (b *ReceiptBuilder) Build() (ReceiptRequestData, error) {
+	errs := append([]error{}, b.errs...)
+	errs = append(errs, ValidateReceipt(b.receipt)...)
+
+	if err := errors.Join(errs...); err != nil {
+		return ReceiptRequestData{}, fmt.Errorf("invalid receipt: %w", err)
+	}
+
+	return b.receipt, nil
+}
+
+// ValidateReceipt checks the receipt against the limits of the API and returns all violations.
+func ValidateReceipt(receipt ReceiptRequestData) []error {
+	var errs []error
+
+	if len(receipt.Items) == 0 {
+		errs = append(errs, errors.New("receipt has no items"))
+	}
+	if len(receipt.Items) > MaxReceiptItems {
+		errs = append(errs, fmt.Errorf("receipt has %d items, at most %d are allowed", len(receipt.Items), MaxReceiptItems))
+	}
+	if receipt.Customer.Email == "" && receipt.Customer.Phone == "" && receipt.Email == "" && receipt.Phone == "" {
+		errs = append(errs, errors.New("customer email or phone is required"))
+	}
+
+	for i, item := range receipt.Items {
+		if l := utf8.RuneCountInString(item.Description); l < 1 || l > 128 {
+			errs = append(errs, fmt.Errorf("item %d: description must be 1-128 characters", i+1))
+		}
+		if price, err := item.Amount.MinorUnits(); err != nil || price <= 0 {
+			errs = append(errs, fmt.Errorf("item %d: price must be positive", i+1))
+		}
+		if qty, err := ParseQuantity(item.Quantity); err != nil || qty <= 0 {
+			errs = append(errs, fmt.Errorf("item %d: quantity must be positive", i+1))
+		}
+		if item.PaymentSubject != "" && !item.PaymentSubject.IsValid() {
+			errs = append(errs, fmt.Errorf("item %d: invalid payment subject %q", i+1, item.PaymentSubject))
+		}
+		if item.PaymentMode != "" && !item.PaymentMode.IsValid() {
+			errs = append(errs, fmt.Errorf("item %d: invalid payment mode %q", i+1, item.PaymentMode))
+		}
+		if item.Measure != "" && !item.Measure.IsValid() {
+			errs = append(errs, fmt.Errorf("item %d: invalid measure %q", i+1, item.Measure))
+		}
+		if item.AgentType != "" {
+			switch {
+			case !item.AgentType.IsValid():
+				errs = append(errs, fmt.Errorf("item %d: invalid agent type %q", i+1, item.AgentType))
+			case item.Supplier == nil || item.Supplier.INN == "":
+				errs = append(errs, fmt.Errorf("item %d: supplier INN is required for agent type %q", i+1, item.AgentType))
+			case !isValidINN(item.Supplier.INN):
+				errs = append(errs, fmt.Errorf("item %d: supplier INN must be 10 or 12 digits", i+1))
+			}
+		}
+		if !item.VatCode.IsValid() {
+			errs = append(errs, fmt.Errorf("item %d: invalid VAT code %d", i+1, item.VatCode))
+		} else if receipt.TaxSystemCode != 0 && !receipt.TaxSystemCode.AllowsVat(item.VatCode) {
+			errs = append(errs, fmt.Errorf("item %d: VAT code %s is not allowed for tax system %s", i+1, item.VatCode, receipt.TaxSystemCode))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,252 @@
+package yookassa
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Locales of the labels.
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+)
+
+type label struct {
+	ru string
+	en string
+}
+
+func (l label) get(locale string) string {
+	if locale == LocaleRU {
+		return l.ru
+	}
+
+	return l.en
+}
+
+// PaymentSubject is the subject of the calculation of the receipt item.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-subject
+type PaymentSubject string
+
+const (
+	PaymentSubjectCommodity                      PaymentSubject = "commodity"
+	PaymentSubjectExcise                         PaymentSubject = "excise"
+	PaymentSubjectJob                            PaymentSubject = "job"
+	PaymentSubjectService                        PaymentSubject = "service"
+	PaymentSubjectGamblingBet                    PaymentSubject = "gambling_bet"
+	PaymentSubjectGamblingPrize                  PaymentSubject = "gambling_prize"
+	PaymentSubjectLottery                        PaymentSubject = "lottery"
+	PaymentSubjectLotteryPrize                   PaymentSubject = "lottery_prize"
+	PaymentSubjectIntellectualActivity           PaymentSubject = "intellectual_activity"
+	PaymentSubjectPayment                        PaymentSubject = "payment"
+	PaymentSubjectAgentCommission                PaymentSubject = "agent_commission"
+	PaymentSubjectPropertyRight                  PaymentSubject = "property_right"
+	PaymentSubjectNonOperatingGain               PaymentSubject = "non_operating_gain"
+	PaymentSubjectInsurancePremium               PaymentSubject = "insurance_premium"
+	PaymentSubjectSalesTax                       PaymentSubject = "sales_tax"
+	PaymentSubjectResortFee                      PaymentSubject = "resort_fee"
+	PaymentSubjectComposite                      PaymentSubject = "composite"
+	PaymentSubjectAnother                        PaymentSubject = "another"
+	PaymentSubjectFine                           PaymentSubject = "fine"
+	PaymentSubjectTax                            PaymentSubject = "tax"
+	PaymentSubjectLien                           PaymentSubject = "lien"
+	PaymentSubjectCost                           PaymentSubject = "cost"
+	PaymentSubjectPensionInsuranceWithoutPayouts PaymentSubject = "pension_insurance_without_payouts"
+	PaymentSubjectPensionInsuranceWithPayouts    PaymentSubject = "pension_insurance_with_payouts"
+	PaymentSubjectHealthInsuranceWithoutPayouts  PaymentSubject = "health_insurance_without_payouts"
+	PaymentSubjectHealthInsuranceWithPayouts     PaymentSubject = "health_insurance_with_payouts"
+	PaymentSubjectHealthInsurance                PaymentSubject = "health_insurance"
+	PaymentSubjectCasino                         PaymentSubject = "casino"
+	PaymentSubjectAgentWithdrawals               PaymentSubject = "agent_withdrawals"
+	PaymentSubjectNonMarkedExcise                PaymentSubject = "non_marked_excise"
+	PaymentSubjectMarkedExcise                   PaymentSubject = "marked_excise"
+	PaymentSubjectMarked                         PaymentSubject = "marked"
+	PaymentSubjectNonMarked                      PaymentSubject = "non_marked"
+)
+
+var paymentSubjectLabels = map[PaymentSubject]label{
+	PaymentSubjectCommodity:                      {"Товар", "Commodity"},
+	PaymentSubjectExcise:                         {"Подакцизный товар", "Excise commodity"},
+	PaymentSubjectJob:                            {"Работа", "Job"},
+	PaymentSubjectService:                        {"Услуга", "Service"},
+	PaymentSubjectGamblingBet:                    {"Ставка в азартной игре", "Gambling bet"},
+	PaymentSubjectGamblingPrize:                  {"Выигрыш в азартной игре", "Gambling prize"},
+	PaymentSubjectLottery:                        {"Лотерейный билет", "Lottery ticket"},
+	PaymentSubjectLotteryPrize:                   {"Выигрыш в лотерею", "Lottery prize"},
+	PaymentSubjectIntellectualActivity:           {"Результаты интеллектуальной деятельности", "Intellectual activity"},
+	PaymentSubjectPayment:                        {"Платеж", "Payment"},
+	PaymentSubjectAgentCommission:                {"Агентское вознаграждение", "Agent commission"},
+	PaymentSubjectPropertyRight:                  {"Имущественные права", "Property right"},
+	PaymentSubjectNonOperatingGain:               {"Внереализационный доход", "Non-operating gain"},
+	PaymentSubjectInsurancePremium:               {"Страховой сбор", "Insurance premium"},
+	PaymentSubjectSalesTax:                       {"Торговый сбор", "Sales tax"},
+	PaymentSubjectResortFee:                      {"Курортный сбор", "Resort fee"},
+	PaymentSubjectComposite:                      {"Несколько вариантов", "Composite"},
+	PaymentSubjectAnother:                        {"Другое", "Another"},
+	PaymentSubjectFine:                           {"Выплата", "Payout"},
+	PaymentSubjectTax:                            {"Страховые взносы", "Insurance contributions"},
+	PaymentSubjectLien:                           {"Залог", "Lien"},
+	PaymentSubjectCost:                           {"Расход", "Cost"},
+	PaymentSubjectPensionInsuranceWithoutPayouts: {"Взносы на ОПС ИП", "Pension insurance contributions of entrepreneur"},
+	PaymentSubjectPensionInsuranceWithPayouts:    {"Взносы на ОПС", "Pension insurance contributions"},
+	PaymentSubjectHealthInsuranceWithoutPayouts:  {"Взносы на ОМС ИП", "Health insurance contributions of entrepreneur"},
+	PaymentSubjectHealthInsuranceWithPayouts:     {"Взносы на ОМС", "Health insurance contributions"},
+	PaymentSubjectHealthInsurance:                {"Взносы на ОСС", "Social insurance contributions"},
+	PaymentSubjectCasino:                         {"Платеж казино", "Casino payment"},
+	PaymentSubjectAgentWithdrawals:               {"Выдача денежных средств", "Cash withdrawal"},
+	PaymentSubjectNonMarkedExcise:                {"Подакцизный товар без маркировки", "Non-marked excise commodity"},
+	PaymentSubjectMarkedExcise:                   {"Подакцизный товар с маркировкой", "Marked excise commodity"},
+	PaymentSubjectMarked:                         {"Товар с маркировкой", "Marked commodity"},
+	PaymentSubjectNonMarked:                      {"Товар без маркировки", "Non-marked commodity"},
+}
+
+// IsValid reports whether the payment subject is known.
+func (s PaymentSubject) IsValid() bool {
+	_, ok := paymentSubjectLabels[s]
+	return ok
+}
+
+// Label returns the human-readable name in the locale, LocaleRU or LocaleEN.
+func (s PaymentSubject) Label(locale string) string {
+	if l, ok := paymentSubjectLabels[s]; ok {
+		return l.get(locale)
+	}
+
+	return string(s)
+}
+
+func (s PaymentSubject) MarshalJSON() ([]byte, error) {
+	if !s.IsValid() {
+		return nil, fmt.Errorf("invalid payment subject %q", string(s))
+	}
+
+	return json.Marshal(string(s))
+}
+
+// PaymentMode is the way of the calculation of the receipt item.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#payment-mode
+type PaymentMode string
+
+const (
+	PaymentModeFullPrepayment    PaymentMode = "full_prepayment"
+	PaymentModePartialPrepayment PaymentMode = "partial_prepayment"
+	PaymentModeAdvance           PaymentMode = "advance"
+	PaymentModeFullPayment       PaymentMode = "full_payment"
+	PaymentModePartialPayment    PaymentMode = "partial_payment"
+	PaymentModeCredit            PaymentMode = "credit"
+	PaymentModeCreditPayment     PaymentMode = "credit_payment"
+)
+
+var paymentModeLabels = map[PaymentMode]label{
+	PaymentModeFullPrepayment:    {"Предоплата 100%", "Full prepayment"},
+	PaymentModePartialPrepayment: {"Частичная предоплата", "Partial prepayment"},
+	PaymentModeAdvance:           {"Аванс", "Advance"},
+	PaymentModeFullPayment:       {"Полный расчет", "Full payment"},
+	PaymentModePartialPayment:    {"Частичный расчет и кредит", "Partial payment and credit"},
+	PaymentModeCredit:            {"Кредит", "Credit"},
+	PaymentModeCreditPayment:     {"Выплата по кредиту", "Credit payment"},
+}
+
+// IsValid reports whether the payment mode is known.
+func (m PaymentMode) IsValid() bool {
+	_, ok := paymentModeLabels[m]
+	return ok
+}
+
+// Label returns the human-readable name in the locale, LocaleRU or LocaleEN.
+func (m PaymentMode) Label(locale string) string {
+	if l, ok := paymentModeLabels[m]; ok {
+		return l.get(locale)
+	}
+
+	return string(m)
+}
+
+func (m PaymentMode) MarshalJSON() ([]byte, error) {
+	if !m.IsValid() {
+		return nil, fmt.Errorf("invalid payment mode %q", string(m))
+	}
+
+	return json.Marshal(string(m))
+}
+
+// Measure is the unit of measurement of the receipt item.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#measure
+type Measure string
+
+const (
+	MeasurePiece            Measure = "piece"
+	MeasureGram             Measure = "gram"
+	MeasureKilogram         Measure = "kilogram"
+	MeasureTon              Measure = "ton"
+	MeasureCentimeter       Measure = "centimeter"
+	MeasureDecimeter        Measure = "decimeter"
+	MeasureMeter            Measure = "meter"
+	MeasureSquareCentimeter Measure = "square_centimeter"
+	MeasureSquareDecimeter  Measure = "square_decimeter"
+	MeasureSquareMeter      Measure = "square_meter"
+	MeasureMilliliter       Measure = "milliliter"
+	MeasureLiter            Measure = "liter"
+	MeasureCubicMeter       Measure = "cubic_meter"
+	MeasureKilowattHour     Measure = "kilowatt_hour"
+	MeasureGigacalorie      Measure = "gigacalorie"
+	MeasureDay              Measure = "day"
+	MeasureHour             Measure = "hour"
+	MeasureMinute           Measure = "minute"
+	MeasureSecond           Measure = "second"
+	MeasureKilobyte         Measure = "kilobyte"
+	MeasureMegabyte         Measure = "megabyte"
+	MeasureGigabyte         Measure = "gigabyte"
+	MeasureTerabyte         Measure = "terabyte"
+	MeasureAnother          Measure = "another"
+)
+
+var measureLabels = map[Measure]label{
+	MeasurePiece:            {"шт.", "pcs"},
+	MeasureGram:             {"г", "g"},
+	MeasureKilogram:         {"кг", "kg"},
+	MeasureTon:              {"т", "t"},
+	MeasureCentimeter:       {"см", "cm"},
+	MeasureDecimeter:        {"дм", "dm"},
+	MeasureMeter:            {"м", "m"},
+	MeasureSquareCentimeter: {"кв. см", "sq. cm"},
+	MeasureSquareDecimeter:  {"кв. дм", "sq. dm"},
+	MeasureSquareMeter:      {"кв. м", "sq. m"},
+	MeasureMilliliter:       {"мл", "ml"},
+	MeasureLiter:            {"л", "l"},
+	MeasureCubicMeter:       {"куб. м", "cu. m"},
+	MeasureKilowattHour:     {"кВт·ч", "kWh"},
+	MeasureGigacalorie:      {"Гкал", "Gcal"},
+	MeasureDay:              {"сутки", "day"},
+	MeasureHour:             {"час", "hour"},
+	MeasureMinute:           {"мин", "min"},
+	MeasureSecond:           {"с", "s"},
+	MeasureKilobyte:         {"Кбайт", "KB"},
+	MeasureMegabyte:         {"Мбайт", "MB"},
+	MeasureGigabyte:         {"Гбайт", "GB"},
+	MeasureTerabyte:         {"Тбайт", "TB"},
+	MeasureAnother:          {"иная единица", "other unit"},
+}
+
+// IsValid reports whether the measure is known.
+func (m Measure) IsValid() bool {
+	_, ok := measureLabels[m]
+	return ok
+}
+
+// Label returns the human-readable name in the locale, LocaleRU or LocaleEN.
+func (m Measure) Label(locale string) string {
+	if l, ok := measureLabels[m]; ok {
+		return l.get(locale)
+	}
+
+	return string(m)
+}
+
+func (m Measure) MarshalJSON() ([]byte, error) {
+	if !m.IsValid() {
+		return nil, fmt.Errorf("invalid measure %q", string(m))
+	}
+
+	return json.Marshal(string(m))
+}
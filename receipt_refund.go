@@ -0,0 +1,160 @@
+package yookassa
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// RefundReceiptByQuantities builds the refund receipt for the returned items of the original receipt.
+// The keys of returned are indexes of the original items, the values are returned quantities like "1".
+// Prices, VAT codes and marking of the items are preserved.
+func RefundReceiptByQuantities(original ReceiptRequestData, returned map[int]string) (ReceiptRequestData, error) {
+	indexes := make([]int, 0, len(returned))
+	for i := range returned {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	refund := refundReceiptBase(original)
+	for _, i := range indexes {
+		if i < 0 || i >= len(original.Items) {
+			return ReceiptRequestData{}, fmt.Errorf("item %d does not exist in the receipt", i+1)
+		}
+		item := original.Items[i]
+
+		qty, err := ParseQuantity(returned[i])
+		if err != nil {
+			return ReceiptRequestData{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		bought, err := ParseQuantity(item.Quantity)
+		if err != nil {
+			return ReceiptRequestData{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		if qty <= 0 || qty > bought {
+			return ReceiptRequestData{}, fmt.Errorf("item %d: returned quantity %s must be in (0, %s]", i+1, returned[i], item.Quantity)
+		}
+
+		item.Quantity = FormatQuantity(qty)
+		refund.Items = append(refund.Items, item)
+	}
+
+	return refund, validateRefundReceipt(refund)
+}
+
+// RefundReceiptByAmount builds the refund receipt for a part of the payment. The amount is
+// prorated over the items by their cost, the rounding remainders are given to the items with
+// the largest fractions, so the items sum up to the amount exactly. Items which can not be
+// priced exactly for their quantity are split into two lines.
+func RefundReceiptByAmount(original ReceiptRequestData, amount Amount) (ReceiptRequestData, error) {
+	refundTotal, err := amount.MinorUnits()
+	if err != nil {
+		return ReceiptRequestData{}, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	costs := make([]int64, len(original.Items))
+	var total int64
+	for i, item := range original.Items {
+		if costs[i], err = ItemTotal(item); err != nil {
+			return ReceiptRequestData{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		total += costs[i]
+	}
+	if refundTotal <= 0 || refundTotal > total {
+		return ReceiptRequestData{}, fmt.Errorf("refund amount %s must be in (0, %s]", amount.Value, FormatMinorUnits(total))
+	}
+
+	refund := refundReceiptBase(original)
+	for i, share := range prorate(refundTotal, costs) {
+		if share == 0 {
+			continue
+		}
+
+		item := original.Items[i]
+		qty, _ := ParseQuantity(item.Quantity)
+		for _, line := range priceLines(share, qty) {
+			if line.price == 0 {
+				continue
+			}
+			item.Amount = NewAmount(line.price, item.Amount.Currency)
+			item.Quantity = FormatQuantity(line.quantity)
+			refund.Items = append(refund.Items, item)
+		}
+	}
+
+	return refund, validateRefundReceipt(refund)
+}
+
+func refundReceiptBase(original ReceiptRequestData) ReceiptRequestData {
+	return ReceiptRequestData{
+		Customer:               original.Customer,
+		Phone:                  original.Phone,
+		Email:                  original.Email,
+		TaxSystemCode:          original.TaxSystemCode,
+		ReceiptIndustryDetails: original.ReceiptIndustryDetails,
+		ReceiptOperatorDetails: original.ReceiptOperatorDetails,
+	}
+}
+
+func validateRefundReceipt(refund ReceiptRequestData) error {
+	if err := errors.Join(ValidateReceipt(refund)...); err != nil {
+		return fmt.Errorf("invalid refund receipt: %w", err)
+	}
+
+	return nil
+}
+
+// prorate splits the amount over the weights by the largest remainder method.
+func prorate(amount int64, weights []int64) []int64 {
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]int64, len(weights))
+	var distributed int64
+	for i, w := range weights {
+		shares[i] = amount * w / total
+		remainders[i] = amount * w % total
+		distributed += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+
+	for _, i := range order[:amount-distributed] {
+		shares[i]++
+	}
+
+	return shares
+}
+
+type priceLine struct {
+	price    int64
+	quantity int64
+}
+
+// priceLines returns lines whose cost is exactly the share for the quantity in thousandths.
+// If one unit price can not give the share, one unit is moved to a separate line.
+// Lines with zero price may be returned for tiny shares and must be skipped.
+func priceLines(share, quantity int64) []priceLine {
+	unit := share * QuantityScale / quantity
+	for price := unit; price <= unit+QuantityScale/quantity+1; price++ {
+		if MultiplyQuantity(price, quantity) == share {
+			return []priceLine{{price: price, quantity: quantity}}
+		}
+	}
+
+	rest := quantity - QuantityScale
+
+	return []priceLine{
+		{price: unit, quantity: rest},
+		{price: share - MultiplyQuantity(unit, rest), quantity: QuantityScale},
+	}
+}
@@ -0,0 +1,233 @@
+package yookassa
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	texttemplate "text/template"
+	"time"
+)
+
+// ReceiptView is the data passed to the receipt templates.
+type ReceiptView struct {
+	Locale               string // LocaleRU or LocaleEN
+	Type                 string // ReceiptTypePayment or ReceiptTypeRefund. Empty for receipt data of payments
+	Items                []ReceiptItemView
+	VAT                  []ReceiptVATView // VAT by rates in the order of VAT codes. Items without VAT are skipped
+	Total                string           // Sum of the items. As example "10.00"
+	Currency             string
+	CustomerName         string
+	CustomerEmail        string
+	CustomerPhone        string
+	FiscalDocumentNumber string // Fiscal attributes of registered receipts
+	FiscalStorageNumber  string
+	FiscalAttribute      string
+	RegisteredAt         *time.Time
+}
+
+// ReceiptItemView is the item line of the receipt.
+type ReceiptItemView struct {
+	Description    string
+	Quantity       string
+	Measure        string // Label of the measure
+	UnitPrice      string
+	Total          string // Unit price multiplied by quantity
+	VAT            string // VAT rate. As example "20%"
+	VATAmount      string
+	PaymentSubject string // Label of the payment subject
+	PaymentMode    string // Label of the payment mode
+}
+
+// ReceiptVATView is the VAT of all items with the rate.
+type ReceiptVATView struct {
+	Rate   string
+	Amount string
+}
+
+// NewReceiptView prepares the receipt data for rendering with labels in the locale.
+func NewReceiptView(receipt ReceiptRequestData, locale string) (ReceiptView, error) {
+	view := ReceiptView{
+		Locale:        locale,
+		CustomerName:  receipt.Customer.FullName,
+		CustomerEmail: receipt.Customer.Email,
+		CustomerPhone: receipt.Customer.Phone,
+	}
+	if view.CustomerEmail == "" {
+		view.CustomerEmail = receipt.Email
+	}
+	if view.CustomerPhone == "" {
+		view.CustomerPhone = receipt.Phone
+	}
+
+	return view, view.fillItems(receipt.Items)
+}
+
+// NewReceiptResponseView prepares the receipt registered by YooKassa for rendering with labels in the locale.
+func NewReceiptResponseView(receipt ReceiptResponse, locale string) (ReceiptView, error) {
+	view := ReceiptView{
+		Locale:               locale,
+		Type:                 receipt.Type,
+		FiscalDocumentNumber: receipt.FiscalDocumentNumber,
+		FiscalStorageNumber:  receipt.FiscalStorageNumber,
+		FiscalAttribute:      receipt.FiscalAttribute,
+		RegisteredAt:         receipt.RegisteredAt,
+	}
+
+	return view, view.fillItems(receipt.Items)
+}
+
+func (v *ReceiptView) fillItems(items []Items) error {
+	var total int64
+	vat := make(map[VatCode]int64)
+
+	for i, item := range items {
+		cost, err := ItemTotal(item)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i+1, err)
+		}
+		itemVAT := item.VatCode.VATAmount(cost)
+
+		v.Items = append(v.Items, ReceiptItemView{
+			Description:    item.Description,
+			Quantity:       item.Quantity,
+			Measure:        item.Measure.Label(v.Locale),
+			UnitPrice:      item.Amount.Value,
+			Total:          FormatMinorUnits(cost),
+			VAT:            vatLabel(item.VatCode, v.Locale),
+			VATAmount:      FormatMinorUnits(itemVAT),
+			PaymentSubject: item.PaymentSubject.Label(v.Locale),
+			PaymentMode:    item.PaymentMode.Label(v.Locale),
+		})
+
+		total += cost
+		if item.VatCode != VatNone {
+			vat[item.VatCode] += itemVAT
+		}
+		if v.Currency == "" {
+			v.Currency = item.Amount.Currency
+		}
+	}
+
+	codes := make([]VatCode, 0, len(vat))
+	for code := range vat {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(a, b int) bool { return codes[a] < codes[b] })
+	for _, code := range codes {
+		v.VAT = append(v.VAT, ReceiptVATView{Rate: vatLabel(code, v.Locale), Amount: FormatMinorUnits(vat[code])})
+	}
+
+	v.Total = FormatMinorUnits(total)
+
+	return nil
+}
+
+func vatLabel(code VatCode, locale string) string {
+	if code == VatNone {
+		return label{"Без НДС", "No VAT"}.get(locale)
+	}
+
+	return code.String()
+}
+
+// receiptLabels are the headers of the default templates.
+var receiptLabels = map[string]label{
+	"receipt":  {"Чек", "Receipt"},
+	"refund":   {"Чек возврата", "Refund receipt"},
+	"total":    {"Итого", "Total"},
+	"vat":      {"НДС", "VAT"},
+	"customer": {"Покупатель", "Customer"},
+	"fiscal":   {"ФД", "Fiscal document"},
+	"storage":  {"ФН", "Fiscal storage"},
+	"sign":     {"ФП", "Fiscal sign"},
+}
+
+const defaultReceiptTextTemplate = `{{if eq .Type "refund"}}{{label "refund" .Locale}}{{else}}{{label "receipt" .Locale}}{{end}}
+{{range $i, $item := .Items}}
+{{$item.Description}}
+  {{$item.Quantity}}{{with $item.Measure}} {{.}}{{end}} x {{$item.UnitPrice}} = {{$item.Total}}
+  {{$item.VAT}}{{if ne $item.VATAmount "0.00"}}: {{$item.VATAmount}}{{end}}{{with $item.PaymentSubject}}, {{.}}{{end}}{{with $item.PaymentMode}}, {{.}}{{end}}
+{{end}}
+{{label "total" .Locale}}: {{.Total}} {{.Currency}}
+{{range .VAT}}{{label "vat" $.Locale}} {{.Rate}}: {{.Amount}}
+{{end}}{{if or .CustomerName .CustomerEmail .CustomerPhone}}{{label "customer" .Locale}}:{{with .CustomerName}} {{.}}{{end}}{{with .CustomerEmail}} {{.}}{{end}}{{with .CustomerPhone}} {{.}}{{end}}
+{{end}}{{with .FiscalDocumentNumber}}{{label "fiscal" $.Locale}}: {{.}}
+{{end}}{{with .FiscalStorageNumber}}{{label "storage" $.Locale}}: {{.}}
+{{end}}{{with .FiscalAttribute}}{{label "sign" $.Locale}}: {{.}}
+{{end}}`
+
+const defaultReceiptHTMLTemplate = `<div class="receipt">
+<h2>{{if eq .Type "refund"}}{{label "refund" .Locale}}{{else}}{{label "receipt" .Locale}}{{end}}</h2>
+<table class="receipt-items">
+{{range .Items}}<tr>
+<td>{{.Description}}{{if or .PaymentSubject .PaymentMode}}<br><small>{{.PaymentSubject}}{{if and .PaymentSubject .PaymentMode}}, {{end}}{{.PaymentMode}}</small>{{end}}</td>
+<td>{{.Quantity}}{{with .Measure}} {{.}}{{end}} &times; {{.UnitPrice}}</td>
+<td>{{.Total}}</td>
+<td>{{.VAT}}</td>
+</tr>
+{{end}}</table>
+<p class="receipt-total">{{label "total" .Locale}}: {{.Total}} {{.Currency}}</p>
+<ul class="receipt-vat">
+{{range .VAT}}<li>{{label "vat" $.Locale}} {{.Rate}}: {{.Amount}}</li>
+{{end}}</ul>
+{{if or .CustomerName .CustomerEmail .CustomerPhone}}<p class="receipt-customer">{{label "customer" .Locale}}:{{with .CustomerName}} {{.}}{{end}}{{with .CustomerEmail}} {{.}}{{end}}{{with .CustomerPhone}} {{.}}{{end}}</p>
+{{end}}{{if .FiscalDocumentNumber}}<p class="receipt-fiscal">{{label "fiscal" .Locale}}: {{.FiscalDocumentNumber}}, {{label "storage" .Locale}}: {{.FiscalStorageNumber}}, {{label "sign" .Locale}}: {{.FiscalAttribute}}</p>
+{{end}}</div>
+`
+
+// ReceiptRenderer renders receipts as plain text and HTML.
+type ReceiptRenderer struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// ReceiptRendererOption configures the ReceiptRenderer.
+type ReceiptRendererOption func(*ReceiptRenderer)
+
+// WithReceiptTextTemplate replaces the default plain text template. The template receives ReceiptView.
+func WithReceiptTextTemplate(tmpl *texttemplate.Template) ReceiptRendererOption {
+	return func(r *ReceiptRenderer) {
+		r.text = tmpl
+	}
+}
+
+// WithReceiptHTMLTemplate replaces the default HTML template. The template receives ReceiptView.
+func WithReceiptHTMLTemplate(tmpl *htmltemplate.Template) ReceiptRendererOption {
+	return func(r *ReceiptRenderer) {
+		r.html = tmpl
+	}
+}
+
+// ReceiptTemplateFuncs returns the functions used by the default templates, so overriding
+// templates can use them too: label returns the localized header by its key and locale.
+func ReceiptTemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"label": func(key, locale string) string { return receiptLabels[key].get(locale) },
+	}
+}
+
+// NewReceiptRenderer creates a renderer with the default templates.
+func NewReceiptRenderer(opts ...ReceiptRendererOption) *ReceiptRenderer {
+	funcs := ReceiptTemplateFuncs()
+	r := &ReceiptRenderer{
+		text: texttemplate.Must(texttemplate.New("receipt").Funcs(funcs).Parse(defaultReceiptTextTemplate)),
+		html: htmltemplate.Must(htmltemplate.New("receipt").Funcs(funcs).Parse(defaultReceiptHTMLTemplate)),
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// RenderText writes the receipt as plain text.
+func (r *ReceiptRenderer) RenderText(w io.Writer, view ReceiptView) error {
+	return r.text.Execute(w, view)
+}
+
+// RenderHTML writes the receipt as HTML. The data is escaped.
+func (r *ReceiptRenderer) RenderHTML(w io.Writer, view ReceiptView) error {
+	return r.html.Execute(w, view)
+}
@@ -0,0 +1,171 @@
+package yookassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// prepaymentVatCodes maps calculated VAT rates of prepayment receipts to the rates of the final settlement.
+var prepaymentVatCodes = map[VatCode]VatCode{
+	Vat10_110: Vat10,
+	Vat20_120: Vat20,
+	Vat5_105:  Vat5,
+	Vat7_107:  Vat7,
+	Vat22_122: Vat22,
+}
+
+// NewFinalSettlementReceipt builds the second receipt required by 54-FZ when goods paid in advance
+// are shipped. The items of the prepayment receipt get the full_payment mode and the calculated
+// VAT rates are replaced with the regular ones. The whole amount of the payment is settled as
+// prepayment, so the sum of the items must be equal to it.
+//
+// Only full_prepayment items are supported, use NewPartialSettlementReceipt after partial_prepayment
+// or advance receipts.
+func NewFinalSettlementReceipt(payment PaymentResponse, prepayment ReceiptRequestData) (ReceiptRequest, error) {
+	if err := checkSettledPayment(payment); err != nil {
+		return ReceiptRequest{}, err
+	}
+	for i, item := range prepayment.Items {
+		if item.PaymentMode != "" && item.PaymentMode != PaymentModeFullPrepayment {
+			return ReceiptRequest{}, fmt.Errorf("item %d: payment mode %s is not a full prepayment", i+1, item.PaymentMode)
+		}
+	}
+
+	paid, err := payment.Amount.MinorUnits()
+	if err != nil {
+		return ReceiptRequest{}, fmt.Errorf("invalid payment amount: %w", err)
+	}
+
+	items, total, err := finalSettlementItems(prepayment.Items)
+	if err != nil {
+		return ReceiptRequest{}, err
+	}
+	if total != paid {
+		return ReceiptRequest{}, fmt.Errorf("items total %s does not match payment amount %s",
+			FormatMinorUnits(total), payment.Amount.Value)
+	}
+
+	return finalSettlementReceipt(payment, prepayment, items, []Settlements{{
+		Type:   SettlementPrepayment,
+		Amount: payment.Amount,
+	}})
+}
+
+// NewPartialSettlementReceipt builds the final settlement receipt after a partial_prepayment or
+// advance receipt. The goods hold the full price of the shipped items, they get the full_payment
+// mode and the regular VAT rates. The amount of the payment is settled as prepayment and the rest
+// of the goods total as postpayment, so the total must not be less than the payment amount.
+func NewPartialSettlementReceipt(payment PaymentResponse, prepayment ReceiptRequestData, goods []Items) (ReceiptRequest, error) {
+	if err := checkSettledPayment(payment); err != nil {
+		return ReceiptRequest{}, err
+	}
+	for i, item := range prepayment.Items {
+		if item.PaymentMode != PaymentModePartialPrepayment && item.PaymentMode != PaymentModeAdvance {
+			return ReceiptRequest{}, fmt.Errorf("item %d: payment mode %s is not a partial prepayment or advance", i+1, item.PaymentMode)
+		}
+	}
+
+	paid, err := payment.Amount.MinorUnits()
+	if err != nil {
+		return ReceiptRequest{}, fmt.Errorf("invalid payment amount: %w", err)
+	}
+
+	items, total, err := finalSettlementItems(goods)
+	if err != nil {
+		return ReceiptRequest{}, err
+	}
+	if total < paid {
+		return ReceiptRequest{}, fmt.Errorf("items total %s is less than payment amount %s",
+			FormatMinorUnits(total), payment.Amount.Value)
+	}
+
+	settlements := []Settlements{{
+		Type:   SettlementPrepayment,
+		Amount: payment.Amount,
+	}}
+	if rest := total - paid; rest > 0 {
+		settlements = append(settlements, Settlements{
+			Type:   SettlementPostpayment,
+			Amount: NewAmount(rest, payment.Amount.Currency),
+		})
+	}
+
+	return finalSettlementReceipt(payment, prepayment, items, settlements)
+}
+
+func checkSettledPayment(payment PaymentResponse) error {
+	if payment.Status != StatusSucceeded {
+		return fmt.Errorf("payment %s is %s, only succeeded payments can be settled", payment.ID, payment.Status)
+	}
+
+	return nil
+}
+
+// finalSettlementItems copies the items with the full_payment mode and the regular VAT rates
+// and returns their total in minor units.
+func finalSettlementItems(source []Items) ([]Items, int64, error) {
+	items := make([]Items, len(source))
+	var total int64
+	for i, item := range source {
+		if code, ok := prepaymentVatCodes[item.VatCode]; ok {
+			item.VatCode = code
+		}
+		item.PaymentMode = PaymentModeFullPayment
+		items[i] = item
+
+		cost, err := ItemTotal(item)
+		if err != nil {
+			return nil, 0, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		total += cost
+	}
+
+	return items, total, nil
+}
+
+// finalSettlementReceipt builds the receipt of the items for the customer of the prepayment receipt.
+func finalSettlementReceipt(payment PaymentResponse, prepayment ReceiptRequestData, items []Items, settlements []Settlements) (ReceiptRequest, error) {
+	customer := prepayment.Customer
+	if customer.Email == "" && customer.Phone == "" {
+		customer.Email = prepayment.Email
+		customer.Phone = prepayment.Phone
+	}
+
+	receipt := ReceiptRequest{
+		Type:          ReceiptTypePayment,
+		PaymentID:     payment.ID,
+		Customer:      customer,
+		Items:         items,
+		Send:          true,
+		TaxSystemCode: prepayment.TaxSystemCode,
+		Settlements:   settlements,
+	}
+
+	if err := errors.Join(ValidateReceipt(ReceiptRequestData{Customer: customer, Items: items, TaxSystemCode: prepayment.TaxSystemCode})...); err != nil {
+		return ReceiptRequest{}, fmt.Errorf("invalid receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// SendFinalSettlementReceipt builds the final settlement receipt of the payment and creates it.
+func (c *yooKassaClient) SendFinalSettlementReceipt(ctx context.Context, payment PaymentResponse, prepayment ReceiptRequestData) (ReceiptResponse, error) {
+	receipt, err := NewFinalSettlementReceipt(payment, prepayment)
+	if err != nil {
+		return ReceiptResponse{}, err
+	}
+
+	return c.SendReceiptRequest(ctx, receipt)
+}
+
+// SendPartialSettlementReceipt builds the final settlement receipt of the goods after a partial
+// prepayment and creates it.
+func (c *yooKassaClient) SendPartialSettlementReceipt(ctx context.Context, payment PaymentResponse, prepayment ReceiptRequestData, goods []Items) (ReceiptResponse, error) {
+	receipt, err := NewPartialSettlementReceipt(payment, prepayment, goods)
+	if err != nil {
+		return ReceiptResponse{}, err
+	}
+
+	return c.SendReceiptRequest(ctx, receipt)
+}
@@ -0,0 +1,139 @@
+package yookassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrReceiptRegistrationCanceled is reported when the tax service did not register the receipt.
+	ErrReceiptRegistrationCanceled = errors.New("receipt registration canceled")
+	// ErrNoReceiptRegistration is reported when the payment succeeded without a receipt.
+	ErrNoReceiptRegistration = errors.New("payment has no receipt registration")
+)
+
+// ReceiptAPI is the part of the client used by ReceiptTracker.
+type ReceiptAPI interface {
+	GetPayment(ctx context.Context, paymentID string) (PaymentResponse, error)
+	ListReceipts(ctx context.Context, paymentID string) ([]ReceiptResponse, error)
+}
+
+// ReceiptTrackerConfig configures the ReceiptTracker.
+type ReceiptTrackerConfig struct {
+	Backoff PollBackoff // Polling of the payment. Zero fields keep the defaults of WithPollBackoff
+
+	// OnRegistered receives the registered receipts with their fiscal attributes.
+	OnRegistered func(ctx context.Context, paymentID string, receipts []ReceiptResponse)
+	// OnFailed receives the error of the registration or the polling.
+	OnFailed func(ctx context.Context, paymentID string, err error)
+	// Observe receives the final status of the registration, as example
+	// metrics.Collector.ObserveReceiptRegistration.
+	Observe func(status ReceiptRegistrationStatus)
+}
+
+// ReceiptTracker watches the receipt registration of payments until it is final.
+// Use OnFailed to alert on receipts the tax service did not register, and Observe
+// to count the results. A payment canceled before the receipt was sent has no receipt
+// registration, its tracking ends without calling the callbacks.
+type ReceiptTracker struct {
+	api    ReceiptAPI
+	config ReceiptTrackerConfig
+}
+
+// NewReceiptTracker creates a tracker which polls the API.
+func NewReceiptTracker(api ReceiptAPI, config ReceiptTrackerConfig) *ReceiptTracker {
+	backoff := defaultPollBackoff
+	if config.Backoff.Initial > 0 {
+		backoff.Initial = config.Backoff.Initial
+	}
+	if config.Backoff.Max > 0 {
+		backoff.Max = config.Backoff.Max
+	}
+	if config.Backoff.Multiplier >= 1 {
+		backoff.Multiplier = config.Backoff.Multiplier
+	}
+	config.Backoff = backoff
+
+	return &ReceiptTracker{api: api, config: config}
+}
+
+// Track polls the payment until its receipt registration is final and returns the registered
+// receipts of the payment. The callbacks are called with the result. A canceled payment without
+// a receipt registration returns no receipts and no error.
+func (t *ReceiptTracker) Track(ctx context.Context, paymentID string) ([]ReceiptResponse, error) {
+	status, receipts, err := t.track(ctx, paymentID)
+	if status != "" && t.config.Observe != nil {
+		t.config.Observe(status)
+	}
+	if err != nil {
+		if t.config.OnFailed != nil {
+			t.config.OnFailed(ctx, paymentID, err)
+		}
+		return nil, err
+	}
+
+	if status == ReceiptRegistrationSucceeded && t.config.OnRegistered != nil {
+		t.config.OnRegistered(ctx, paymentID, receipts)
+	}
+
+	return receipts, nil
+}
+
+// Start tracks the payment in a new goroutine. The result is passed to the callbacks only.
+func (t *ReceiptTracker) Start(ctx context.Context, paymentID string) {
+	go func() {
+		_, _ = t.Track(ctx, paymentID)
+	}()
+}
+
+// track returns the final status of the registration, or "" if the payment has no registration.
+func (t *ReceiptTracker) track(ctx context.Context, paymentID string) (ReceiptRegistrationStatus, []ReceiptResponse, error) {
+	interval := t.config.Backoff.Initial
+	for {
+		payment, err := t.api.GetPayment(ctx, paymentID)
+		if err != nil {
+			if apiErr, ok := AsAPIError(err); (ok && isClientError(apiErr.StatusCode)) || ctx.Err() != nil {
+				return "", nil, err
+			}
+		} else {
+			switch {
+			case payment.ReceiptRegistration == ReceiptRegistrationSucceeded:
+				receipts, err := t.registeredReceipts(ctx, paymentID)
+				return ReceiptRegistrationSucceeded, receipts, err
+			case payment.ReceiptRegistration == ReceiptRegistrationCanceled:
+				return ReceiptRegistrationCanceled, nil, fmt.Errorf("%w: payment %s", ErrReceiptRegistrationCanceled, paymentID)
+			case payment.ReceiptRegistration == "" && payment.Status == StatusCanceled:
+				return "", nil, nil
+			case payment.ReceiptRegistration == "" && payment.Status.IsTerminal():
+				return "", nil, fmt.Errorf("%w: payment %s", ErrNoReceiptRegistration, paymentID)
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval = t.config.Backoff.next(interval)
+	}
+}
+
+func (t *ReceiptTracker) registeredReceipts(ctx context.Context, paymentID string) ([]ReceiptResponse, error) {
+	receipts, err := t.api.ListReceipts(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	registered := receipts[:0]
+	for _, r := range receipts {
+		if r.Status == ReceiptRegistrationSucceeded {
+			registered = append(registered, r)
+		}
+	}
+
+	return registered, nil
+}
@@ -0,0 +1,167 @@
+package yookassa
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// redactedPersonalData replaces personal data when there is no key to hash it.
+const redactedPersonalData = "[REDACTED]"
+
+// Keys of the API objects which hold personal data. Their values are replaced with hashes by RedactJSON.
+var personalDataKeys = map[string]bool{
+	"full_name":                      true,
+	"first_name":                     true, // Passengers of airline tickets
+	"last_name":                      true,
+	"inn":                            true,
+	"email":                          true,
+	"phone":                          true,
+	"cardholder":                     true,
+	"merchant_customer_id":           true, // Email or phone of the customer in the shop
+	"topped_up_phone":                true,
+	"merchant_customer_bank_account": true,
+	"account_number":                 true, // Bank account of the payer
+}
+
+// MaskPAN masks the card number keeping the first 6 and the last 4 digits.
+// As example "5555555555554477" becomes "555555******4477".
+func MaskPAN(pan string) string {
+	if len(pan) < 10 {
+		return strings.Repeat("*", len(pan))
+	}
+
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
+
+// HashPersonalData replaces the value with its HMAC-SHA256 keyed by key, so equal values
+// can still be matched in the logs. Keep the key out of the logs and use at least 32 random bytes,
+// so phones and INNs can not be found by brute force. Without a key the value is replaced
+// with "[REDACTED]". Empty values stay empty.
+func HashPersonalData(key []byte, value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(key) == 0 {
+		return redactedPersonalData
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// RedactJSON returns a copy of the JSON document with sensitive data removed:
+// card numbers are masked, CSC is dropped and personal data is hashed with HashPersonalData
+// keyed by key. Documents which are not valid JSON are replaced with a placeholder.
+func RedactJSON(data []byte, key []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return []byte(`"<non-JSON body redacted>"`)
+	}
+
+	redacted, err := json.Marshal(redactValue(v, "", key))
+	if err != nil {
+		return []byte(`"<body redacted>"`)
+	}
+
+	return redacted
+}
+
+func redactValue(v interface{}, parentKey string, hashKey []byte) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			switch {
+			case key == "csc":
+				delete(v, key)
+			case key == "number" && parentKey == "card":
+				if s, ok := value.(string); ok {
+					v[key] = MaskPAN(s)
+				}
+			case personalDataKeys[key]:
+				if s, ok := value.(string); ok {
+					v[key] = HashPersonalData(hashKey, s)
+				} else {
+					v[key] = redactValue(value, key, hashKey)
+				}
+			default:
+				v[key] = redactValue(value, key, hashKey)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, parentKey, hashKey)
+		}
+	}
+
+	return v
+}
+
+// redactedBankCardData has the same fields as BankCardData but no formatting methods.
+type redactedBankCardData BankCardData
+
+func (d BankCardData) redacted() redactedBankCardData {
+	return redactedBankCardData{
+		Number:      MaskPAN(d.Number),
+		ExpiryYear:  d.ExpiryYear,
+		ExpiryMonth: d.ExpiryMonth,
+		Cardholder:  HashPersonalData(nil, d.Cardholder),
+	}
+}
+
+// LogValue implements slog.LogValuer. The card number is masked, CSC is omitted
+// and the cardholder is redacted.
+func (d BankCardData) LogValue() slog.Value {
+	r := d.redacted()
+
+	return slog.GroupValue(
+		slog.String("number", r.Number),
+		slog.String("expiry_year", r.ExpiryYear),
+		slog.String("expiry_month", r.ExpiryMonth),
+		slog.String("cardholder", r.Cardholder),
+	)
+}
+
+// Format implements fmt.Formatter so printing the card never exposes its number or CSC.
+func (d BankCardData) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), d.redacted())
+}
+
+// redactedCustomer has the same fields as Customer but no formatting methods.
+type redactedCustomer Customer
+
+func (c Customer) redacted() redactedCustomer {
+	return redactedCustomer{
+		FullName: HashPersonalData(nil, c.FullName),
+		INN:      HashPersonalData(nil, c.INN),
+		Email:    HashPersonalData(nil, c.Email),
+		Phone:    HashPersonalData(nil, c.Phone),
+	}
+}
+
+// LogValue implements slog.LogValuer. Personal data is redacted.
+func (c Customer) LogValue() slog.Value {
+	r := c.redacted()
+
+	return slog.GroupValue(
+		slog.String("full_name", r.FullName),
+		slog.String("inn", r.INN),
+		slog.String("email", r.Email),
+		slog.String("phone", r.Phone),
+	)
+}
+
+// Format implements fmt.Formatter so printing the customer never exposes personal data.
+func (c Customer) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), c.redacted())
+}
@@ -0,0 +1,40 @@
+package yookassa
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RefundStatus is the status of the refund.
+type RefundStatus string
+
+// Statuses of the refund.
+// See https://yookassa.ru/developers/api#refund_object_status
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusCanceled  RefundStatus = "canceled"
+)
+
+// RefundResponse is the refund of the payment.
+// See https://yookassa.ru/developers/api#refund_object
+type RefundResponse struct {
+	ID                  string                    `json:"id"`
+	PaymentID           string                    `json:"payment_id"`
+	Status              RefundStatus              `json:"status"`
+	Amount              Amount                    `json:"amount"`
+	Created             time.Time                 `json:"created_at"`
+	Description         string                    `json:"description,omitempty"`
+	ReceiptRegistration ReceiptRegistrationStatus `json:"receipt_registration,omitempty"`
+}
+
+// GetRefund retrieves the refund with the given ID.
+func (c *yooKassaClient) GetRefund(ctx context.Context, refundID string) (RefundResponse, error) {
+	var refundResponse RefundResponse
+	if err := c.do(ctx, OperationGetRefund, http.MethodGet, c.baseURL+"/refunds/"+refundID, nil, &refundResponse); err != nil {
+		return RefundResponse{}, err
+	}
+
+	return refundResponse, nil
+}
@@ -0,0 +1,242 @@
+package yookassa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrUnknownTenant is returned by Registry when no shop is configured for the tenant.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// ShopConfig describes one shop of the Registry.
+type ShopConfig struct {
+	Tenant    string                  // Key used to route operations. As example legal entity or currency
+	ShopID    string                  // ID of the shop. YooKassa sends it as recipient.account_id
+	APIKey    string                  // Secret key of the shop
+	GatewayID string                  // ID of the subaccount. Optional, used to route notifications
+	Options   []func(*yooKassaClient) // Options applied after the registry options
+}
+
+// PaymentTenantLookup finds the tenant of the payment, as example in the own database of payments.
+// It returns ok false if the payment is unknown.
+type PaymentTenantLookup func(ctx context.Context, paymentID string) (tenant string, ok bool, err error)
+
+// shopGateway is the key of the tenant routing.
+type shopGateway struct {
+	shopID    string
+	gatewayID string
+}
+
+// Registry holds clients of several shops and routes operations and notifications by tenant.
+// All clients share one HTTP client unless a shop overrides it. Tenants of the same shop with
+// different gateways share the rate limits of the first tenant registered for the shop, as the
+// limits of YooKassa apply to the whole shop. Set WithRateLimit in the options of that tenant.
+type Registry struct {
+	mu           sync.RWMutex
+	clients      map[string]*yooKassaClient
+	byRecipient  map[shopGateway]string
+	byShop       map[string][]string
+	httpClient   *http.Client
+	options      []func(*yooKassaClient)
+	refundLookup PaymentTenantLookup
+}
+
+// NewRegistry creates an empty registry. The options are applied to every shop.
+func NewRegistry(opts ...func(*yooKassaClient)) *Registry {
+	return &Registry{
+		clients:     make(map[string]*yooKassaClient),
+		byRecipient: make(map[shopGateway]string),
+		byShop:      make(map[string][]string),
+		httpClient:  &http.Client{},
+		options:     opts,
+	}
+}
+
+// SetRefundLookup sets the lookup used to route refund notifications by the ID of their payment.
+// Refunds have no recipient. Without the lookup the payment is requested from every shop.
+func (r *Registry) SetRefundLookup(lookup PaymentTenantLookup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refundLookup = lookup
+}
+
+// Add configures the shop. It fails if the tenant or the pair of the shop and the gateway
+// is already registered, or if the shop is registered and the options set its rate limits again.
+func (r *Registry) Add(shop ShopConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.clients[shop.Tenant]; ok {
+		return fmt.Errorf("tenant %q is already registered", shop.Tenant)
+	}
+	key := shopGateway{shopID: shop.ShopID, gatewayID: shop.GatewayID}
+	if tenant, ok := r.byRecipient[key]; ok {
+		return fmt.Errorf("shop %q with gateway %q is already registered for tenant %q", shop.ShopID, shop.GatewayID, tenant)
+	}
+
+	tenants := r.byShop[shop.ShopID]
+	if len(tenants) > 0 && setsRateLimits(shop.Options) {
+		return fmt.Errorf("shop %q shares the rate limits of tenant %q, set them in the options of that tenant", shop.ShopID, tenants[0])
+	}
+
+	opts := append([]func(*yooKassaClient){WithHTTPClient(r.httpClient)}, r.options...)
+	opts = append(opts, shop.Options...)
+
+	c := NewConfig(shop.ShopID, shop.APIKey, opts...)
+	if len(tenants) > 0 {
+		c.rateLimiters = r.clients[tenants[0]].rateLimiters
+	}
+
+	r.clients[shop.Tenant] = c
+	r.byRecipient[key] = shop.Tenant
+	r.byShop[shop.ShopID] = append(r.byShop[shop.ShopID], shop.Tenant)
+
+	return nil
+}
+
+// Client returns the client of the tenant.
+func (r *Registry) Client(tenant string) (*yooKassaClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.clients[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, tenant)
+	}
+
+	return c, nil
+}
+
+// SendPaymentRequest creates the payment in the shop of the tenant.
+func (r *Registry) SendPaymentRequest(ctx context.Context, tenant string, paymentRequest PaymentRequest) (PaymentResponse, error) {
+	c, err := r.Client(tenant)
+	if err != nil {
+		return PaymentResponse{}, err
+	}
+
+	return c.SendPaymentRequest(ctx, paymentRequest)
+}
+
+// GetPayment retrieves the payment from the shop of the tenant.
+func (r *Registry) GetPayment(ctx context.Context, tenant, paymentID string) (PaymentResponse, error) {
+	c, err := r.Client(tenant)
+	if err != nil {
+		return PaymentResponse{}, err
+	}
+
+	return c.GetPayment(ctx, paymentID)
+}
+
+// TenantByRecipient finds the tenant of the recipient. The tenant registered for the shop and
+// the gateway takes precedence, then the tenant registered for the shop without a gateway.
+// If the shop has a single tenant, it is used for every gateway.
+func (r *Registry) TenantByRecipient(recipient Recipient) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if tenant, ok := r.byRecipient[shopGateway{shopID: recipient.AccountID, gatewayID: recipient.GatewayID}]; ok {
+		return tenant, true
+	}
+	if tenant, ok := r.byRecipient[shopGateway{shopID: recipient.AccountID}]; ok {
+		return tenant, true
+	}
+	if tenants := r.byShop[recipient.AccountID]; len(tenants) == 1 {
+		return tenants[0], true
+	}
+
+	return "", false
+}
+
+// TenantByPayment finds the tenant of the payment with the lookup set by SetRefundLookup.
+// Without the lookup the payment is requested from every shop and routed by its recipient.
+// Shops which fail to answer are skipped, their errors are returned only if no shop has the payment.
+func (r *Registry) TenantByPayment(ctx context.Context, paymentID string) (string, bool, error) {
+	r.mu.RLock()
+	lookup := r.refundLookup
+	shops := make([]*yooKassaClient, 0, len(r.byShop))
+	for _, tenants := range r.byShop {
+		shops = append(shops, r.clients[tenants[0]])
+	}
+	r.mu.RUnlock()
+
+	if lookup != nil {
+		return lookup(ctx, paymentID)
+	}
+
+	var errs []error
+	for _, c := range shops {
+		payment, err := c.GetPayment(ctx, paymentID)
+		if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		tenant, ok := r.TenantByRecipient(payment.Recipient)
+
+		return tenant, ok, nil
+	}
+
+	return "", false, errors.Join(errs...)
+}
+
+// setsRateLimits reports whether the options set rate limits with WithRateLimit.
+func setsRateLimits(opts []func(*yooKassaClient)) bool {
+	var probe yooKassaClient
+	for _, o := range opts {
+		o(&probe)
+	}
+
+	return len(probe.rateLimiters) > 0
+}
+
+// WebhookHandler returns an http.Handler which passes notifications to handle together with
+// the tenant found by the recipient of the notification object. Refunds are routed by their
+// payment with TenantByPayment. Notifications of unknown recipients are answered with 500,
+// so YooKassa delivers them again.
+func (r *Registry) WebhookHandler(handle func(ctx context.Context, tenant string, notification Notification) error) http.Handler {
+	return NewWebhookHandler(func(ctx context.Context, notification Notification) error {
+		tenant, err := r.notificationTenant(ctx, notification)
+		if err != nil {
+			return err
+		}
+
+		return handle(ctx, tenant, notification)
+	})
+}
+
+func (r *Registry) notificationTenant(ctx context.Context, notification Notification) (string, error) {
+	var object struct {
+		PaymentID string    `json:"payment_id"`
+		Recipient Recipient `json:"recipient"`
+	}
+	if err := json.Unmarshal(notification.Object, &object); err != nil {
+		return "", fmt.Errorf("failed to unmarshal notification object: %w", err)
+	}
+
+	if object.Recipient.AccountID == "" && object.PaymentID != "" {
+		tenant, ok, err := r.TenantByPayment(ctx, object.PaymentID)
+		if err != nil {
+			return "", fmt.Errorf("failed to find tenant of payment %q: %w", object.PaymentID, err)
+		}
+		if !ok {
+			return "", fmt.Errorf("%w: payment %q", ErrUnknownTenant, object.PaymentID)
+		}
+
+		return tenant, nil
+	}
+
+	tenant, ok := r.TenantByRecipient(object.Recipient)
+	if !ok {
+		return "", fmt.Errorf("%w: recipient %q", ErrUnknownTenant, object.Recipient.AccountID)
+	}
+
+	return tenant, nil
+}
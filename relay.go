@@ -0,0 +1,215 @@
+package yookassa
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers set by Relay on forwarded notifications.
+const (
+	RelaySignatureHeader = "X-Relay-Signature" // Hex HMAC-SHA256 of the timestamp, "." and the body
+	RelayTimestampHeader = "X-Relay-Timestamp" // Unix time of the delivery in seconds
+)
+
+// RelayTarget is an internal endpoint receiving notifications.
+type RelayTarget struct {
+	Name        string // Name of the target used in the delivery logs
+	URL         string // URL the notifications are posted to
+	MaxAttempts int    // Attempts to deliver a notification. Default 3
+}
+
+// RelayConfig configures the Relay.
+type RelayConfig struct {
+	Targets    []RelayTarget
+	Secret     []byte        // Secret shared with the targets to sign the notifications
+	HTTPClient *http.Client  // Client used for deliveries. Default http.Client with 10 seconds timeout
+	Backoff    time.Duration // Delay between attempts, multiplied by the attempt number. Default 1 second
+	Logger     *slog.Logger  // Logger of the deliveries. Default slog.Default()
+}
+
+// Relay forwards notifications to internal services, signing each of them with HMAC.
+// The signature only proves the notification passed the relay, so verify the notifications
+// before: accept them with AllowYooKassaIPs and wrap Handle with VerifyNotifications.
+//
+//	handle := yookassa.VerifyNotifications(client, relay.Handle)
+//	http.Handle("/webhook", yookassa.AllowYooKassaIPs(yookassa.NewWebhookHandler(handle)))
+type Relay struct {
+	config RelayConfig
+}
+
+// NewRelay creates a relay to the targets.
+func NewRelay(config RelayConfig) *Relay {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = time.Second
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	return &Relay{config: config}
+}
+
+// Handle delivers the notification to all targets concurrently. It implements NotificationHandlerFunc.
+// If any target fails all attempts an error is returned, so YooKassa delivers the notification
+// again and targets which already received it get a duplicate.
+func (r *Relay) Handle(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	errs := make([]error, len(r.config.Targets))
+
+	var wg sync.WaitGroup
+	for i, target := range r.config.Targets {
+		wg.Add(1)
+		go func(i int, target RelayTarget) {
+			defer wg.Done()
+			errs[i] = r.deliver(ctx, target, notification.Event, body)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (r *Relay) deliver(ctx context.Context, target RelayTarget, event string, body []byte) error {
+	maxAttempts := target.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(r.config.Backoff * time.Duration(attempt-1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		start := time.Now()
+		var statusCode int
+		statusCode, err = r.send(ctx, target, body)
+
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelWarn
+		}
+		r.config.Logger.LogAttrs(ctx, level, "yookassa relay delivery",
+			slog.String("target", target.Name),
+			slog.String("event", event),
+			slog.Int("attempt", attempt),
+			slog.Int("status", statusCode),
+			slog.Duration("duration", time.Since(start)),
+			slog.Any("error", err),
+		)
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to relay notification to %s: %w", target.Name, err)
+}
+
+func (r *Relay) send(ctx context.Context, target RelayTarget, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(RelayTimestampHeader, timestamp)
+	req.Header.Set(RelaySignatureHeader, SignRelayPayload(r.config.Secret, timestamp, body))
+
+	resp, err := r.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("request failed with status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignRelayPayload returns the hex HMAC-SHA256 of the timestamp, "." and the body.
+func SignRelayPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DefaultRelayMaxBodySize is the body size limit of VerifyRelaySignature by default.
+const DefaultRelayMaxBodySize = 1 << 20
+
+// VerifyRelaySignature returns a middleware for the targets of Relay. It answers 401 to requests
+// without a valid signature or with a timestamp older or newer than tolerance, and 413 to requests
+// with a body larger than maxBodySize. Zero maxBodySize means DefaultRelayMaxBodySize.
+func VerifyRelaySignature(secret []byte, tolerance time.Duration, maxBodySize int64) func(http.Handler) http.Handler {
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultRelayMaxBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timestamp := r.Header.Get(RelayTimestampHeader)
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid timestamp", http.StatusUnauthorized)
+				return
+			}
+			if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+				http.Error(w, "expired timestamp", http.StatusUnauthorized)
+				return
+			}
+
+			// The body is read before the signature is checked, so its size is limited.
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, "failed to read body", http.StatusBadRequest)
+				return
+			}
+
+			expected := SignRelayPayload(secret, timestamp, body)
+			if !hmac.Equal([]byte(expected), []byte(r.Header.Get(RelaySignatureHeader))) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package yookassa
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SQLPlaceholder formats the n-th parameter of a query, starting from 1.
+// Use QuestionPlaceholder for MySQL and SQLite, DollarPlaceholder for PostgreSQL.
+type SQLPlaceholder func(n int) string
+
+var (
+	QuestionPlaceholder SQLPlaceholder = func(int) string { return "?" }
+	DollarPlaceholder   SQLPlaceholder = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// rebind replaces "?" in the query with the placeholders.
+func (p SQLPlaceholder) rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(p(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package yookassa
+
+import (
+	"fmt"
+)
+
+// VatCode is the VAT rate of the receipt item.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#vat-codes
+type VatCode int
+
+const (
+	VatNone   VatCode = 1  // Without VAT
+	Vat0      VatCode = 2  // 0%
+	Vat10     VatCode = 3  // 10%
+	Vat20     VatCode = 4  // 20%
+	Vat10_110 VatCode = 5  // Calculated rate 10/110
+	Vat20_120 VatCode = 6  // Calculated rate 20/120
+	Vat5      VatCode = 7  // 5%
+	Vat7      VatCode = 8  // 7%
+	Vat5_105  VatCode = 9  // Calculated rate 5/105
+	Vat7_107  VatCode = 10 // Calculated rate 7/107
+	Vat22     VatCode = 11 // 22%, in force since 2026-01-01
+	Vat22_122 VatCode = 12 // Calculated rate 22/122, in force since 2026-01-01
+)
+
+// vatRates holds the rate in percent of every known VAT code.
+var vatRates = map[VatCode]int64{
+	VatNone:   0,
+	Vat0:      0,
+	Vat10:     10,
+	Vat20:     20,
+	Vat10_110: 10,
+	Vat20_120: 20,
+	Vat5:      5,
+	Vat7:      7,
+	Vat5_105:  5,
+	Vat7_107:  7,
+	Vat22:     22,
+	Vat22_122: 22,
+}
+
+// IsValid reports whether the VAT code is known.
+func (c VatCode) IsValid() bool {
+	_, ok := vatRates[c]
+	return ok
+}
+
+// Rate returns the rate in percent. It is zero for VatNone and Vat0.
+func (c VatCode) Rate() int64 {
+	return vatRates[c]
+}
+
+// IsCalculated reports whether the rate is a calculated one like 20/120, used for prepayments.
+func (c VatCode) IsCalculated() bool {
+	return c == Vat10_110 || c == Vat20_120 || c == Vat5_105 || c == Vat7_107 || c == Vat22_122
+}
+
+func (c VatCode) String() string {
+	switch {
+	case c == VatNone:
+		return "none"
+	case !c.IsValid():
+		return fmt.Sprintf("VatCode(%d)", int(c))
+	case c.IsCalculated():
+		return fmt.Sprintf("%d/%d", c.Rate(), 100+c.Rate())
+	default:
+		return fmt.Sprintf("%d%%", c.Rate())
+	}
+}
+
+// VATAmount returns the VAT included into the total in minor units, rounded half up.
+func (c VatCode) VATAmount(total int64) int64 {
+	rate := c.Rate()
+	if rate == 0 {
+		return 0
+	}
+
+	return divRound(total*rate, 100+rate)
+}
+
+// ItemTotal returns the cost of the item (price multiplied by quantity) in minor units.
+func ItemTotal(item Items) (int64, error) {
+	price, err := item.Amount.MinorUnits()
+	if err != nil {
+		return 0, err
+	}
+	qty, err := ParseQuantity(item.Quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return MultiplyQuantity(price, qty), nil
+}
+
+// ItemVAT returns the VAT included into the cost of the item in minor units.
+func ItemVAT(item Items) (int64, error) {
+	total, err := ItemTotal(item)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.VatCode.VATAmount(total), nil
+}
+
+// ReceiptVAT returns the VAT of the receipt by VAT codes in minor units.
+func ReceiptVAT(receipt ReceiptRequestData) (map[VatCode]int64, error) {
+	vat := make(map[VatCode]int64)
+	for i, item := range receipt.Items {
+		amount, err := ItemVAT(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		vat[item.VatCode] += amount
+	}
+
+	return vat, nil
+}
+
+// TaxSystem is the tax system of the shop.
+// See https://yookassa.ru/developers/payment-acceptance/receipts/54fz/yoomoney/parameters-values#tax-systems
+type TaxSystem int
+
+const (
+	TaxSystemGeneral             TaxSystem = 1 // General (OSN)
+	TaxSystemSimplifiedIncome    TaxSystem = 2 // Simplified, income (USN)
+	TaxSystemSimplifiedNetIncome TaxSystem = 3 // Simplified, income minus expenses (USN)
+	TaxSystemImputedIncome       TaxSystem = 4 // Single tax on imputed income (ENVD)
+	TaxSystemAgricultural        TaxSystem = 5 // Unified agricultural tax (ESHN)
+	TaxSystemPatent              TaxSystem = 6 // Patent (PSN)
+)
+
+func (s TaxSystem) String() string {
+	switch s {
+	case TaxSystemGeneral:
+		return "general"
+	case TaxSystemSimplifiedIncome:
+		return "simplified_income"
+	case TaxSystemSimplifiedNetIncome:
+		return "simplified_net_income"
+	case TaxSystemImputedIncome:
+		return "imputed_income"
+	case TaxSystemAgricultural:
+		return "agricultural"
+	case TaxSystemPatent:
+		return "patent"
+	default:
+		return fmt.Sprintf("TaxSystem(%d)", int(s))
+	}
+}
+
+// AllowsVat reports whether items with the VAT code may be sold under the tax system.
+// Reduced 5% and 7% rates are available only to simplified tax systems, imputed and patent
+// systems do not pay VAT at all. The 22% rate replaced 20% on 2026-01-01, the 20% codes are
+// still accepted for corrections of earlier sales.
+func (s TaxSystem) AllowsVat(c VatCode) bool {
+	if !c.IsValid() {
+		return false
+	}
+
+	switch s {
+	case TaxSystemGeneral, TaxSystemAgricultural:
+		return c != Vat5 && c != Vat7 && c != Vat5_105 && c != Vat7_107
+	case TaxSystemSimplifiedIncome, TaxSystemSimplifiedNetIncome:
+		return true
+	case TaxSystemImputedIncome, TaxSystemPatent:
+		return c == VatNone
+	default:
+		return false
+	}
+}
@@ -0,0 +1,59 @@
+package yookassa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Notification is the body of the webhook notification sent by YooKassa.
+// See https://yookassa.ru/developers/using-api/webhooks
+type Notification struct {
+	Type   string          `json:"type"`   // Type of the object. Always "notification"
+	Event  string          `json:"event"`  // Event of the notification. As example "payment.succeeded"
+	Object json.RawMessage `json:"object"` // Object of the event. Payment or refund depending on the event
+}
+
+// Payment decodes the object of the notification as a payment.
+func (n Notification) Payment() (PaymentResponse, error) {
+	var payment PaymentResponse
+	if err := json.Unmarshal(n.Object, &payment); err != nil {
+		return PaymentResponse{}, fmt.Errorf("failed to unmarshal payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// NotificationHandlerFunc processes a notification. A returned error makes the
+// webhook handler answer with 500 so YooKassa delivers the notification again.
+type NotificationHandlerFunc func(ctx context.Context, notification Notification) error
+
+type webhookHandler struct {
+	handle NotificationHandlerFunc
+}
+
+// NewWebhookHandler returns an http.Handler which decodes notifications and passes them to handle.
+func NewWebhookHandler(handle NotificationHandlerFunc) http.Handler {
+	return &webhookHandler{handle: handle}
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var notification Notification
+	if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+		http.Error(w, "invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(r.Context(), notification); err != nil {
+		http.Error(w, "failed to process notification", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,192 @@
+package yookassa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"strings"
+)
+
+// ErrNotificationNotVerified is returned by VerifyNotifications when the object of the
+// notification does not match the object retrieved from the API.
+var ErrNotificationNotVerified = errors.New("notification is not verified")
+
+// YooKassaNetworks are the networks YooKassa sends notifications from.
+// See https://yookassa.ru/developers/using-api/webhooks#ip
+var YooKassaNetworks = []netip.Prefix{
+	netip.MustParsePrefix("185.71.76.0/27"),
+	netip.MustParsePrefix("185.71.77.0/27"),
+	netip.MustParsePrefix("77.75.153.0/25"),
+	netip.MustParsePrefix("77.75.156.11/32"),
+	netip.MustParsePrefix("77.75.156.35/32"),
+	netip.MustParsePrefix("77.75.154.128/25"),
+	netip.MustParsePrefix("2a02:5180::/32"),
+}
+
+// IsYooKassaIP reports whether the address belongs to YooKassaNetworks.
+func IsYooKassaIP(addr string) bool {
+	ip, err := netip.ParseAddr(strings.TrimSpace(addr))
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+
+	for _, network := range YooKassaNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowYooKassaIPs returns a middleware which answers 403 to requests sent not from YooKassaNetworks.
+// The address is taken from RemoteAddr. Behind a reverse proxy restore the client address
+// before the middleware, the forwarded headers can not be trusted by default.
+func AllowYooKassaIPs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		if !IsYooKassaIP(host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PaymentGetter retrieves payments. It is implemented by the client.
+type PaymentGetter interface {
+	GetPayment(ctx context.Context, paymentID string) (PaymentResponse, error)
+}
+
+// RefundGetter retrieves refunds. It is implemented by the client.
+type RefundGetter interface {
+	GetRefund(ctx context.Context, refundID string) (RefundResponse, error)
+}
+
+// NotificationAPI retrieves the objects of notifications. It is implemented by the client.
+type NotificationAPI interface {
+	PaymentGetter
+	RefundGetter
+}
+
+// VerifyNotifications returns a NotificationHandlerFunc which checks the notification against
+// the API before passing it to handle, so forged notifications never reach handle.
+//
+// The object of the notification is retrieved by its ID. A payment must be in the status of
+// the notification or in a later one, a refund must be in the status of the notification and
+// belong to the same payment. The amount and the metadata of the object must match the retrieved
+// ones. Other fields are not compared, handle receives the object as sent by YooKassa.
+// Other events are rejected.
+func VerifyNotifications(api NotificationAPI, handle NotificationHandlerFunc) NotificationHandlerFunc {
+	return func(ctx context.Context, notification Notification) error {
+		var object struct {
+			ID        string                 `json:"id"`
+			PaymentID string                 `json:"payment_id"`
+			Status    string                 `json:"status"`
+			Amount    Amount                 `json:"amount"`
+			Metadata  map[string]interface{} `json:"metadata"`
+		}
+		if err := json.Unmarshal(notification.Object, &object); err != nil {
+			return fmt.Errorf("failed to unmarshal notification object: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(notification.Event, "payment."):
+			payment, err := verifiedPayment(ctx, api, object.ID)
+			if err != nil {
+				return err
+			}
+			if !PaymentStatus(object.Status).CanTransitionTo(payment.Status) {
+				return fmt.Errorf("%w: payment %q is %q, notification claims %q",
+					ErrNotificationNotVerified, payment.ID, payment.Status, object.Status)
+			}
+			if !sameAmount(object.Amount, payment.Amount) || !sameMetadata(object.Metadata, payment.Metadata) {
+				return fmt.Errorf("%w: payment %q does not match the notification", ErrNotificationNotVerified, payment.ID)
+			}
+		case strings.HasPrefix(notification.Event, "refund."):
+			refund, err := verifiedRefund(ctx, api, object.ID)
+			if err != nil {
+				return err
+			}
+			if RefundStatus(object.Status) != refund.Status {
+				return fmt.Errorf("%w: refund %q is %q, notification claims %q",
+					ErrNotificationNotVerified, refund.ID, refund.Status, object.Status)
+			}
+			if object.PaymentID != refund.PaymentID || !sameAmount(object.Amount, refund.Amount) {
+				return fmt.Errorf("%w: refund %q does not match the notification", ErrNotificationNotVerified, refund.ID)
+			}
+		default:
+			return fmt.Errorf("%w: unsupported event %q", ErrNotificationNotVerified, notification.Event)
+		}
+
+		return handle(ctx, notification)
+	}
+}
+
+// verifiedPayment retrieves the payment. Unknown payments are reported as not verified.
+func verifiedPayment(ctx context.Context, api PaymentGetter, paymentID string) (PaymentResponse, error) {
+	if paymentID == "" {
+		return PaymentResponse{}, fmt.Errorf("%w: no payment ID", ErrNotificationNotVerified)
+	}
+
+	payment, err := api.GetPayment(ctx, paymentID)
+	if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode == http.StatusNotFound {
+		return PaymentResponse{}, fmt.Errorf("%w: unknown payment %q", ErrNotificationNotVerified, paymentID)
+	}
+	if err != nil {
+		return PaymentResponse{}, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// verifiedRefund retrieves the refund. Unknown refunds are reported as not verified.
+func verifiedRefund(ctx context.Context, api RefundGetter, refundID string) (RefundResponse, error) {
+	if refundID == "" {
+		return RefundResponse{}, fmt.Errorf("%w: no refund ID", ErrNotificationNotVerified)
+	}
+
+	refund, err := api.GetRefund(ctx, refundID)
+	if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode == http.StatusNotFound {
+		return RefundResponse{}, fmt.Errorf("%w: unknown refund %q", ErrNotificationNotVerified, refundID)
+	}
+	if err != nil {
+		return RefundResponse{}, fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	return refund, nil
+}
+
+// sameAmount reports whether the amounts are equal, "10.0" and "10.00" are the same value.
+func sameAmount(a, b Amount) bool {
+	if a.Currency != b.Currency {
+		return false
+	}
+
+	x, errX := a.MinorUnits()
+	y, errY := b.MinorUnits()
+	if errX != nil || errY != nil {
+		return a.Value == b.Value
+	}
+
+	return x == y
+}
+
+// sameMetadata reports whether the metadata are equal, missing metadata equals empty one.
+func sameMetadata(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}